@@ -0,0 +1,285 @@
+package rod
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/go-rod/rod/lib/utils"
+	"github.com/ysmood/gson"
+)
+
+// AXNode is a node of the accessibility tree of the page
+type AXNode struct {
+	ID          proto.AccessibilityAXNodeID
+	Role        string
+	Name        string
+	Description string
+	Value       string
+
+	// Ignored is true if the node is ignored for accessibility, such as a div without any role
+	Ignored bool
+
+	// States of the node, such as "disabled", "checked", "level", etc
+	States map[proto.AccessibilityAXPropertyName]gson.JSON
+
+	// BackendNodeID of the related DOM node, it's zero if the node has no related DOM node
+	BackendNodeID proto.DOMBackendNodeID
+
+	Parent   *AXNode
+	Children []*AXNode
+
+	// Raw data from the cdp
+	Raw *proto.AccessibilityAXNode
+
+	page *Page
+}
+
+// AXTree returns the root of the accessibility tree of the page
+func (p *Page) AXTree() (*AXNode, error) {
+	defer p.EnableDomain(&proto.AccessibilityEnable{})()
+
+	res, err := proto.AccessibilityGetFullAXTree{}.Call(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.Nodes) == 0 {
+		return nil, &ErrElementNotFound{}
+	}
+
+	dict := map[proto.AccessibilityAXNodeID]*AXNode{}
+	for _, raw := range res.Nodes {
+		dict[raw.NodeID] = newAXNode(p, raw)
+	}
+
+	for _, raw := range res.Nodes {
+		node := dict[raw.NodeID]
+		for _, id := range raw.ChildIds {
+			if child, has := dict[id]; has {
+				child.Parent = node
+				node.Children = append(node.Children, child)
+			}
+		}
+	}
+
+	// the first node is the root document
+	return dict[res.Nodes[0].NodeID], nil
+}
+
+func newAXNode(p *Page, raw *proto.AccessibilityAXNode) *AXNode {
+	node := &AXNode{
+		ID:            raw.NodeID,
+		Role:          axValue(raw.Role),
+		Name:          axValue(raw.Name),
+		Description:   axValue(raw.Description),
+		Value:         axValue(raw.Value),
+		Ignored:       raw.Ignored,
+		States:        map[proto.AccessibilityAXPropertyName]gson.JSON{},
+		BackendNodeID: raw.BackendDOMNodeID,
+		Raw:           raw,
+		page:          p,
+	}
+
+	for _, prop := range raw.Properties {
+		if prop.Value != nil && !prop.Value.Value.Nil() {
+			node.States[prop.Name] = prop.Value.Value
+		}
+	}
+
+	return node
+}
+
+func axValue(v *proto.AccessibilityAXValue) string {
+	if v == nil || v.Value.Nil() {
+		return ""
+	}
+	if s, ok := v.Value.Val().(string); ok {
+		return s
+	}
+	return v.Value.JSON("", "")
+}
+
+// Walk the node and its descendants in depth-first order, return false in the callback to skip
+// the children of the node.
+func (n *AXNode) Walk(fn func(*AXNode) bool) {
+	if !fn(n) {
+		return
+	}
+	for _, child := range n.Children {
+		child.Walk(fn)
+	}
+}
+
+// Find the descendants that have the role and name, an empty string matches any value.
+// The ignored nodes are skipped.
+func (n *AXNode) Find(role, name string) []*AXNode {
+	list := []*AXNode{}
+	n.Walk(func(node *AXNode) bool {
+		if !node.Ignored && (role == "" || node.Role == role) && (name == "" || node.Name == name) {
+			list = append(list, node)
+		}
+		return true
+	})
+	return list
+}
+
+// Element of the node, returns ErrElementNotFound if the node has no related DOM node
+func (n *AXNode) Element() (*Element, error) {
+	if n.BackendNodeID == 0 {
+		return nil, &ErrElementNotFound{}
+	}
+	return n.page.ElementFromNode(&proto.DOMNode{BackendNodeID: n.BackendNodeID})
+}
+
+// AXSnapshot is a serializable form of the accessibility tree, the ignored nodes are removed and
+// their children are moved to their parents. It's useful to compare the trees of different builds.
+type AXSnapshot struct {
+	Role        string                 `json:"role,omitempty"`
+	Name        string                 `json:"name,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Value       string                 `json:"value,omitempty"`
+	States      map[string]interface{} `json:"states,omitempty"`
+	Children    []*AXSnapshot          `json:"children,omitempty"`
+}
+
+// Snapshot of the node and its descendants
+func (n *AXNode) Snapshot() *AXSnapshot {
+	list := n.snapshot()
+	if len(list) == 1 {
+		return list[0]
+	}
+	return &AXSnapshot{Children: list}
+}
+
+func (n *AXNode) snapshot() []*AXSnapshot {
+	children := []*AXSnapshot{}
+	for _, child := range n.Children {
+		children = append(children, child.snapshot()...)
+	}
+
+	if n.Ignored {
+		return children
+	}
+
+	s := &AXSnapshot{
+		Role:        n.Role,
+		Name:        n.Name,
+		Description: n.Description,
+		Value:       n.Value,
+	}
+	if len(children) > 0 {
+		s.Children = children
+	}
+	for k, v := range n.States {
+		if s.States == nil {
+			s.States = map[string]interface{}{}
+		}
+		s.States[string(k)] = v.Val()
+	}
+
+	return []*AXSnapshot{s}
+}
+
+// String returns the indented text of the tree, each line is a node, such as:
+//
+//     RootWebArea "Title"
+//       button "Save" disabled=true
+//
+// The output is stable, so it can be used to diff the trees.
+func (s *AXSnapshot) String() string {
+	sb := &strings.Builder{}
+	s.write(sb, 0)
+	return sb.String()
+}
+
+func (s *AXSnapshot) write(sb *strings.Builder, depth int) {
+	sb.WriteString(strings.Repeat("  ", depth))
+	sb.WriteString(s.Role)
+	if s.Name != "" {
+		sb.WriteString(fmt.Sprintf(" %q", s.Name))
+	}
+	if s.Value != "" {
+		sb.WriteString(fmt.Sprintf(" value=%q", s.Value))
+	}
+	if s.Description != "" {
+		sb.WriteString(fmt.Sprintf(" description=%q", s.Description))
+	}
+
+	keys := make([]string, 0, len(s.States))
+	for k := range s.States {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		sb.WriteString(fmt.Sprintf(" %s=%s", k, utils.MustToJSON(s.States[k])))
+	}
+	sb.WriteString("\n")
+
+	for _, child := range s.Children {
+		child.write(sb, depth+1)
+	}
+}
+
+// ElementByRole retries until an element in the page that has the accessible role and name, then returns
+// the matched element. An empty name matches any name.
+func (p *Page) ElementByRole(role, name string) (*Element, error) {
+	var node *proto.AccessibilityAXNode
+
+	err := utils.Retry(p.ctx, p.sleeper(), func() (bool, error) {
+		doc, err := proto.DOMGetDocument{}.Call(p)
+		if err != nil {
+			return true, err
+		}
+
+		node, err = p.queryAXTree(proto.AccessibilityQueryAXTree{
+			BackendNodeID:  doc.Root.BackendNodeID,
+			Role:           role,
+			AccessibleName: name,
+		})
+		return node != nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return p.ElementFromNode(&proto.DOMNode{BackendNodeID: node.BackendDOMNodeID})
+}
+
+// ElementByRole returns the first element in the subtree of el, including el itself, that has the
+// accessible role and name. An empty name matches any name.
+func (el *Element) ElementByRole(role, name string) (*Element, error) {
+	node, err := el.page.queryAXTree(proto.AccessibilityQueryAXTree{
+		ObjectID:       el.id(),
+		Role:           role,
+		AccessibleName: name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		return nil, &ErrElementNotFound{}
+	}
+
+	e, err := el.page.ElementFromNode(&proto.DOMNode{BackendNodeID: node.BackendDOMNodeID})
+	if err != nil {
+		return nil, err
+	}
+	return e.Sleeper(el.sleeper), nil
+}
+
+// returns the first node that is not ignored and has a related DOM node
+func (p *Page) queryAXTree(q proto.AccessibilityQueryAXTree) (*proto.AccessibilityAXNode, error) {
+	res, err := q.Call(p)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, n := range res.Nodes {
+		if !n.Ignored && n.BackendDOMNodeID != 0 {
+			return n, nil
+		}
+	}
+	return nil, nil
+}
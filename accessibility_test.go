@@ -0,0 +1,122 @@
+package rod_test
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func (t T) AXTree() {
+	p := t.page.MustNavigate(t.srcFile("fixtures/locator.html")).MustWaitLoad()
+
+	root := p.MustAXTree()
+	t.Eq("RootWebArea", root.Role)
+	t.Nil(root.Parent)
+
+	list := root.Find("button", "Save")
+	t.Len(list, 2)
+	t.Eq("save", *list[0].MustElement().MustAttribute("data-testid"))
+	t.Eq("button", list[0].Parent.Find("button", "")[0].Role)
+
+	heading := root.Find("heading", "Locator")[0]
+	t.Eq(1, heading.States["level"].Int())
+
+	roles := map[string]bool{}
+	root.Walk(func(n *rod.AXNode) bool {
+		roles[n.Role] = true
+		return n.Role != "form"
+	})
+	t.True(roles["form"])
+	t.False(roles["textbox"])
+
+	_, err := (&rod.AXNode{}).Element()
+	t.Is(err, &rod.ErrElementNotFound{})
+
+	snapshot := root.Snapshot()
+	t.Has(snapshot.String(), `button "Save"`)
+	t.Has(snapshot.String(), `heading "Locator" level=1`)
+
+	var loaded rod.AXSnapshot
+	data, err := json.Marshal(snapshot)
+	t.E(err)
+	t.E(json.Unmarshal(data, &loaded))
+	t.Eq(snapshot.String(), loaded.String())
+}
+
+func (t T) AXSnapshotString() {
+	s := &rod.AXSnapshot{
+		Role: "RootWebArea",
+		Name: "Title",
+		Children: []*rod.AXSnapshot{
+			{Role: "button", Name: "Save", States: map[string]interface{}{"focusable": true, "disabled": true}},
+			{Role: "textbox", Value: "a", Description: "b"},
+		},
+	}
+
+	t.Eq(s.String(), `RootWebArea "Title"
+  button "Save" disabled=true focusable=true
+  textbox value="a" description="b"
+`)
+}
+
+func (t T) ElementByRole() {
+	p := t.page.MustNavigate(t.srcFile("fixtures/locator.html")).MustWaitLoad()
+
+	t.Eq("save", *p.MustElementByRole("button", "Save").MustAttribute("data-testid"))
+	t.Eq("email", *p.MustElementByRole("textbox", "Email Address").MustAttribute("id"))
+	t.Eq("Save", p.MustElement("form").MustElementByRole("button", "").MustText())
+
+	_, err := p.Sleeper(rod.NotFoundSleeper).ElementByRole("button", "nothing")
+	t.Is(err, &rod.ErrElementNotFound{})
+
+	_, err = p.MustElement("form").ElementByRole("link", "")
+	t.Is(err, &rod.ErrElementNotFound{})
+}
+
+func (t T) AuditAccessibility() {
+	p := t.page.MustNavigate(t.srcFile("fixtures/audit.html")).MustWaitLoad()
+
+	report := p.MustAuditAccessibility()
+	t.Has(report.URL, "audit.html")
+
+	t.Len(report.Filter(rod.AuditRuleImageAlt), 1)
+	t.Eq(`<img src="icon.png">`, report.Filter(rod.AuditRuleImageAlt)[0].Node)
+
+	t.Len(report.Filter(rod.AuditRuleLabel), 1)
+	t.Eq("dup", *report.Filter(rod.AuditRuleLabel)[0].MustElement().MustAttribute("id"))
+
+	t.Len(report.Filter(rod.AuditRuleHeadingOrder), 1)
+	t.Eq("Skipped Level", report.Filter(rod.AuditRuleHeadingOrder)[0].MustElement().MustText())
+
+	t.Len(report.Filter(rod.AuditRuleDuplicateID), 1)
+	t.Eq("P", report.Filter(rod.AuditRuleDuplicateID)[0].MustElement().MustEval(`() => this.tagName`).Str())
+
+	contrast := report.Filter(rod.AuditRuleContrast)
+	t.Len(contrast, 1)
+	t.Eq("1.4.3", contrast[0].WCAG)
+	t.Eq("Low Contrast", contrast[0].MustElement().MustText())
+
+	t.Eq(1, report.Summary()[rod.AuditRuleContrast])
+
+	buf := bytes.NewBuffer(nil)
+	t.E(report.WriteJSON(buf))
+	var loaded rod.AuditReport
+	t.E(json.Unmarshal(buf.Bytes(), &loaded))
+	t.Len(loaded.Findings, len(report.Findings))
+
+	buf.Reset()
+	t.E(report.WriteHTML(buf))
+	t.Has(buf.String(), "color-contrast: 1")
+	t.Has(buf.String(), "&lt;img src=&#34;icon.png&#34;&gt;")
+
+	// the error of a single node doesn't stop the audit
+	t.mc.stubErr(1, proto.CSSGetComputedStyleForNode{})
+	report = p.MustAuditAccessibility()
+	t.Len(report.Filter(rod.AuditRuleImageAlt), 1)
+	t.Lte(len(report.Filter(rod.AuditRuleContrast)), 1)
+
+	_, err := (&rod.AuditFinding{}).Element()
+	t.Is(err, &rod.ErrElementNotFound{})
+}
@@ -0,0 +1,450 @@
+package rod
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod/lib/assets"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/go-rod/rod/lib/utils"
+)
+
+// AuditRule of the accessibility audit
+type AuditRule string
+
+const (
+	// AuditRuleImageAlt images must have alternative text
+	AuditRuleImageAlt AuditRule = "image-alt"
+
+	// AuditRuleLabel form controls must have labels
+	AuditRuleLabel AuditRule = "label"
+
+	// AuditRuleContrast text must have sufficient color contrast with its background
+	AuditRuleContrast AuditRule = "color-contrast"
+
+	// AuditRuleHeadingOrder heading levels should only increase by one
+	AuditRuleHeadingOrder AuditRule = "heading-order"
+
+	// AuditRuleDuplicateID id attribute values must be unique
+	AuditRuleDuplicateID AuditRule = "duplicate-id"
+)
+
+// the WCAG success criteria of the rules
+var auditRuleWCAG = map[AuditRule]string{
+	AuditRuleImageAlt:     "1.1.1",
+	AuditRuleLabel:        "4.1.2",
+	AuditRuleContrast:     "1.4.3",
+	AuditRuleHeadingOrder: "1.3.1",
+	AuditRuleDuplicateID:  "4.1.1",
+}
+
+// AuditFinding is a violation of an AuditRule
+type AuditFinding struct {
+	Rule    AuditRule `json:"rule"`
+	WCAG    string    `json:"wcag"`
+	Message string    `json:"message"`
+
+	// Node is a short html of the element, such as `<img src="a.png">`
+	Node string `json:"node,omitempty"`
+
+	// BackendNodeID of the element
+	BackendNodeID proto.DOMBackendNodeID `json:"backendNodeId,omitempty"`
+
+	page *Page
+}
+
+// Element of the finding
+func (f *AuditFinding) Element() (*Element, error) {
+	if f.BackendNodeID == 0 {
+		return nil, &ErrElementNotFound{}
+	}
+	return f.page.ElementFromNode(&proto.DOMNode{BackendNodeID: f.BackendNodeID})
+}
+
+// AuditReport of the Page.AuditAccessibility
+type AuditReport struct {
+	URL      string          `json:"url"`
+	Time     time.Time       `json:"time"`
+	Findings []*AuditFinding `json:"findings"`
+
+	// Issues reported by the Audits domain of the browser, such as the low text contrast issues
+	Issues []*proto.AuditsInspectorIssue `json:"issues,omitempty"`
+}
+
+// Filter the findings of the rule
+func (r *AuditReport) Filter(rule AuditRule) []*AuditFinding {
+	list := []*AuditFinding{}
+	for _, f := range r.Findings {
+		if f.Rule == rule {
+			list = append(list, f)
+		}
+	}
+	return list
+}
+
+// Summary returns the count of findings of each rule
+func (r *AuditReport) Summary() map[AuditRule]int {
+	summary := map[AuditRule]int{}
+	for _, f := range r.Findings {
+		summary[f.Rule]++
+	}
+	return summary
+}
+
+// WriteJSON writes the report as indented json
+func (r *AuditReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+var auditReportTpl = template.Must(template.New("audit").Funcs(template.FuncMap{
+	"json": utils.MustToJSON,
+}).Parse(assets.AuditReport))
+
+// WriteHTML writes the report as a standalone html page
+func (r *AuditReport) WriteHTML(w io.Writer) error {
+	return auditReportTpl.Execute(w, r)
+}
+
+// AuditAccessibility checks the page against the AuditRule list, the checks are based on the
+// accessibility tree, the DOM tree, and the computed styles of the elements. The issues reported
+// by the Audits domain of the browser will also be collected into the report.
+func (p *Page) AuditAccessibility() (*AuditReport, error) {
+	issues := []*proto.AuditsInspectorIssue{}
+	sub := p.Subscribe(EventFilter{}, func(e *proto.AuditsIssueAdded) {
+		issues = append(issues, e.Issue)
+	})
+
+	err := proto.AuditsCheckContrast{}.Call(p)
+	if err != nil {
+		sub.Unsubscribe()
+		return nil, err
+	}
+
+	info, err := p.Info()
+	if err != nil {
+		sub.Unsubscribe()
+		return nil, err
+	}
+
+	a := &accessibilityAudit{page: p}
+	err = a.run()
+	sub.Unsubscribe()
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuditReport{
+		URL:      info.URL,
+		Time:     time.Now(),
+		Findings: a.findings,
+		Issues:   issues,
+	}, nil
+}
+
+type accessibilityAudit struct {
+	page     *Page
+	ax       *AXNode
+	doc      *proto.DOMNode
+	nodes    map[proto.DOMBackendNodeID]*proto.DOMNode
+	parents  map[proto.DOMBackendNodeID]*proto.DOMNode
+	findings []*AuditFinding
+}
+
+func (a *accessibilityAudit) run() error {
+	defer a.page.EnableDomain(&proto.DOMEnable{})()
+	defer a.page.EnableDomain(&proto.CSSEnable{})()
+
+	ax, err := a.page.AXTree()
+	if err != nil {
+		return err
+	}
+	a.ax = ax
+
+	doc, err := proto.DOMGetDocument{Depth: -1, Pierce: true}.Call(a.page)
+	if err != nil {
+		return err
+	}
+	a.doc = doc.Root
+	a.nodes = map[proto.DOMBackendNodeID]*proto.DOMNode{}
+	a.parents = map[proto.DOMBackendNodeID]*proto.DOMNode{}
+	a.index(a.doc, nil)
+
+	a.imageAlt()
+	a.label()
+	a.headingOrder()
+	a.duplicateID()
+	return a.contrast()
+}
+
+func (a *accessibilityAudit) index(node, parent *proto.DOMNode) {
+	a.nodes[node.BackendNodeID] = node
+	if parent != nil {
+		a.parents[node.BackendNodeID] = parent
+	}
+	for _, child := range domChildren(node) {
+		a.index(child, node)
+	}
+}
+
+func domChildren(node *proto.DOMNode) []*proto.DOMNode {
+	list := append([]*proto.DOMNode{}, node.Children...)
+	list = append(list, node.ShadowRoots...)
+	if node.ContentDocument != nil {
+		list = append(list, node.ContentDocument)
+	}
+	return list
+}
+
+func (a *accessibilityAudit) add(rule AuditRule, id proto.DOMBackendNodeID, format string, args ...interface{}) {
+	a.findings = append(a.findings, &AuditFinding{
+		Rule:          rule,
+		WCAG:          auditRuleWCAG[rule],
+		Message:       fmt.Sprintf(format, args...),
+		Node:          domNodeHTML(a.nodes[id]),
+		BackendNodeID: id,
+		page:          a.page,
+	})
+}
+
+func (a *accessibilityAudit) imageAlt() {
+	a.ax.Walk(func(n *AXNode) bool {
+		if !n.Ignored && (n.Role == "img" || n.Role == "image") && strings.TrimSpace(n.Name) == "" {
+			a.add(AuditRuleImageAlt, n.BackendNodeID, "image has no alternative text")
+		}
+		return true
+	})
+}
+
+var auditLabelRoles = map[string]bool{
+	"textbox":    true,
+	"searchbox":  true,
+	"combobox":   true,
+	"listbox":    true,
+	"checkbox":   true,
+	"radio":      true,
+	"slider":     true,
+	"spinbutton": true,
+	"switch":     true,
+}
+
+func (a *accessibilityAudit) label() {
+	a.ax.Walk(func(n *AXNode) bool {
+		if !n.Ignored && auditLabelRoles[n.Role] && strings.TrimSpace(n.Name) == "" {
+			a.add(AuditRuleLabel, n.BackendNodeID, "%s has no label", n.Role)
+		}
+		return true
+	})
+}
+
+func (a *accessibilityAudit) headingOrder() {
+	prev := 0
+	a.ax.Walk(func(n *AXNode) bool {
+		if n.Ignored || n.Role != "heading" {
+			return true
+		}
+		level := n.States["level"].Int()
+		if prev > 0 && level > prev+1 {
+			a.add(AuditRuleHeadingOrder, n.BackendNodeID, "heading level jumps from %d to %d", prev, level)
+		}
+		prev = level
+		return true
+	})
+}
+
+func (a *accessibilityAudit) duplicateID() {
+	// each document and shadow root has its own scope of ids
+	var check func(root *proto.DOMNode)
+	check = func(root *proto.DOMNode) {
+		ids := map[string]int{}
+
+		var walk func(node *proto.DOMNode)
+		walk = func(node *proto.DOMNode) {
+			for _, child := range node.Children {
+				if id, has := domAttr(child, "id"); has && id != "" {
+					ids[id]++
+					if ids[id] == 2 {
+						a.add(AuditRuleDuplicateID, child.BackendNodeID, "id %q is used by more than one element", id)
+					}
+				}
+				walk(child)
+			}
+			for _, s := range node.ShadowRoots {
+				check(s)
+			}
+			if node.ContentDocument != nil {
+				check(node.ContentDocument)
+			}
+		}
+		walk(root)
+	}
+	check(a.doc)
+}
+
+func (a *accessibilityAudit) contrast() error {
+	checked := map[proto.DOMBackendNodeID]bool{}
+	var err error
+
+	a.ax.Walk(func(n *AXNode) bool {
+		if err != nil {
+			return false
+		}
+		if n.Ignored || (n.Role != "StaticText" && n.Role != "text") || strings.TrimSpace(n.Name) == "" {
+			return true
+		}
+
+		el := a.parents[n.BackendNodeID]
+		if el == nil || el.NodeType != 1 || checked[el.BackendNodeID] {
+			return true
+		}
+		checked[el.BackendNodeID] = true
+
+		// the node may be removed or changed during the audit, skip it and keep auditing the rest,
+		// unless the page is canceled
+		if e := a.contrastOf(el); e != nil && a.page.ctx.Err() != nil {
+			err = e
+		}
+		return true
+	})
+
+	return err
+}
+
+func (a *accessibilityAudit) contrastOf(el *proto.DOMNode) error {
+	style, err := proto.CSSGetComputedStyleForNode{NodeID: el.NodeID}.Call(a.page)
+	if err != nil {
+		return err
+	}
+
+	var fg *auditColor
+	for _, prop := range style.ComputedStyle {
+		if prop.Name == "color" {
+			fg = parseAuditColor(prop.Value)
+		}
+	}
+	if fg == nil {
+		return nil
+	}
+
+	bg, err := proto.CSSGetBackgroundColors{NodeID: el.NodeID}.Call(a.page)
+	if err != nil {
+		return err
+	}
+
+	large := isLargeText(bg.ComputedFontSize, bg.ComputedFontWeight)
+	threshold := 4.5
+	if large {
+		threshold = 3
+	}
+
+	// use the worst contrast of all the background colors, such as the color stops of a gradient
+	ratio := math.Inf(1)
+	for _, s := range bg.BackgroundColors {
+		if c := parseAuditColor(s); c != nil {
+			ratio = math.Min(ratio, contrastRatio(fg.over(c), c))
+		}
+	}
+
+	if ratio < threshold {
+		a.add(AuditRuleContrast, el.BackendNodeID, "contrast ratio %.2f:1 is below %.1f:1", ratio, threshold)
+	}
+	return nil
+}
+
+// large text is at least 18pt, or 14pt and bold
+func isLargeText(size, weight string) bool {
+	px, _ := strconv.ParseFloat(strings.TrimSuffix(size, "px"), 64)
+
+	w, err := strconv.Atoi(weight)
+	if err != nil {
+		w = 400
+		if weight == "bold" || weight == "bolder" {
+			w = 700
+		}
+	}
+
+	return px >= 24 || (px >= 18.66 && w >= 700)
+}
+
+type auditColor struct {
+	r, g, b, a float64
+}
+
+var regAuditColor = regexp.MustCompile(`rgba?\(\s*([\d.]+)[,\s]+([\d.]+)[,\s]+([\d.]+)(?:[,\s/]+([\d.]+))?\s*\)`)
+
+func parseAuditColor(s string) *auditColor {
+	m := regAuditColor.FindStringSubmatch(s)
+	if m == nil {
+		return nil
+	}
+
+	f := func(s string) float64 {
+		v, _ := strconv.ParseFloat(s, 64)
+		return v
+	}
+
+	c := &auditColor{f(m[1]), f(m[2]), f(m[3]), 1}
+	if m[4] != "" {
+		c.a = f(m[4])
+	}
+	return c
+}
+
+// over blends the color over the background
+func (c *auditColor) over(bg *auditColor) *auditColor {
+	blend := func(x, y float64) float64 { return x*c.a + y*(1-c.a) }
+	return &auditColor{blend(c.r, bg.r), blend(c.g, bg.g), blend(c.b, bg.b), 1}
+}
+
+// https://www.w3.org/TR/WCAG21/#dfn-relative-luminance
+func (c *auditColor) luminance() float64 {
+	f := func(v float64) float64 {
+		v /= 255
+		if v <= 0.03928 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	return 0.2126*f(c.r) + 0.7152*f(c.g) + 0.0722*f(c.b)
+}
+
+// https://www.w3.org/TR/WCAG21/#dfn-contrast-ratio
+func contrastRatio(a, b *auditColor) float64 {
+	l1, l2 := a.luminance(), b.luminance()
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+func domAttr(node *proto.DOMNode, name string) (string, bool) {
+	for i := 0; i+1 < len(node.Attributes); i += 2 {
+		if node.Attributes[i] == name {
+			return node.Attributes[i+1], true
+		}
+	}
+	return "", false
+}
+
+// the opening tag of the element, such as `<img src="a.png">`
+func domNodeHTML(node *proto.DOMNode) string {
+	if node == nil || node.NodeType != 1 {
+		return ""
+	}
+
+	sb := &strings.Builder{}
+	sb.WriteString("<" + node.LocalName)
+	for i := 0; i+1 < len(node.Attributes); i += 2 {
+		sb.WriteString(fmt.Sprintf(` %s="%s"`, node.Attributes[i], template.HTMLEscapeString(node.Attributes[i+1])))
+	}
+	sb.WriteString(">")
+	return sb.String()
+}
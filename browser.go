@@ -10,6 +10,7 @@ package rod
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"sync"
 	"time"
@@ -20,7 +21,6 @@ import (
 	"github.com/go-rod/rod/lib/launcher"
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/go-rod/rod/lib/utils"
-	"github.com/ysmood/goob"
 )
 
 // Browser implements these interfaces
@@ -43,13 +43,18 @@ type Browser struct {
 
 	slowMotion time.Duration // see defaults.slow
 	trace      bool          // see defaults.Trace
+	traceLog   *TraceLog     // see Browser.TraceLog
 	monitor    string
 
 	defaultDevice devices.Device
 
 	client      CDPClient
-	event       *goob.Observable // all the browser events from cdp client
+	event       *eventHub // all the browser events from cdp client
+	eventBuffer EventBuffer
 	targetsLock *sync.Mutex
+	sessions    *sessions // see Browser.resume
+	operators   *sync.Map // see Page.WaitOperator
+	watchdog    *watchdog // see Browser.Watchdog
 
 	// stores all the previous cdp call of same type. Browser doesn't have enough API
 	// for us to retrieve all its internal states. This is an workaround to map them to local.
@@ -68,6 +73,8 @@ func New() *Browser {
 		logger:        DefaultLogger,
 		defaultDevice: devices.LaptopWithMDPIScreen.Landescape(),
 		targetsLock:   &sync.Mutex{},
+		sessions:      newSessions(),
+		operators:     &sync.Map{},
 		states:        &sync.Map{},
 	}
 }
@@ -140,6 +147,13 @@ func (b *Browser) NoDefaultDevice() *Browser {
 // Connect to the browser and start to control it.
 // If fails to connect, try to launch a local browser, if local browser not found try to download one.
 func (b *Browser) Connect() error {
+	if b.watchdog != nil {
+		err := b.watchdog.setup(b)
+		if err != nil {
+			return err
+		}
+	}
+
 	if b.client == nil {
 		u := defaults.URL
 		if u == "" {
@@ -155,6 +169,10 @@ func (b *Browser) Connect() error {
 
 	b.initEvents()
 
+	if b.watchdog != nil {
+		go b.watchdog.watch(b)
+	}
+
 	err = proto.TargetSetDiscoverTargets{Discover: true}.Call(b)
 	if err != nil {
 		return err
@@ -170,6 +188,12 @@ func (b *Browser) Connect() error {
 // Close the browser
 func (b *Browser) Close() error {
 	if b.BrowserContextID == "" {
+		if b.watchdog != nil {
+			b.watchdog.close()
+		}
+		if client, ok := b.client.(*cdp.Client); ok {
+			client.StopReconnect()
+		}
 		return proto.BrowserClose{}.Call(b)
 	}
 	return proto.TargetDisposeBrowserContext{BrowserContextID: b.BrowserContextID}.Call(b)
@@ -231,7 +255,16 @@ func (b *Browser) Pages() (Pages, error) {
 
 // Call raw cdp interface directly
 func (b *Browser) Call(ctx context.Context, sessionID, methodName string, params interface{}) (res []byte, err error) {
-	res, err = b.client.Call(ctx, sessionID, methodName, params)
+	if b.watchdog != nil {
+		return b.watchdog.call(ctx, "", methodName, func(ctx context.Context) ([]byte, error) {
+			return b.call(ctx, sessionID, methodName, params)
+		})
+	}
+	return b.call(ctx, sessionID, methodName, params)
+}
+
+func (b *Browser) call(ctx context.Context, sessionID, methodName string, params interface{}) (res []byte, err error) {
+	res, err = b.client.Call(ctx, string(b.sessions.current(proto.TargetSessionID(sessionID))), methodName, params)
 	if err != nil {
 		return nil, err
 	}
@@ -297,6 +330,11 @@ func (b *Browser) PageFromTarget(targetID proto.TargetTargetID) (*Page, error) {
 	// Such as proto.PageAddScriptToEvaluateOnNewDocument won't work.
 	page.EnableDomain(&proto.PageEnable{})
 
+	// to receive the proto.InspectorTargetCrashed
+	if b.watchdog != nil {
+		page.EnableDomain(&proto.InspectorEnable{})
+	}
+
 	return page, nil
 }
 
@@ -337,9 +375,28 @@ func (b *Browser) waitEvent(sessionID proto.TargetSessionID, e proto.Event) (wai
 // If the any callback returns true the event loop will stop.
 // It will enable the related domains if not enabled, and restore them after wait ends.
 func (b *Browser) eachEvent(sessionID proto.TargetSessionID, callbacks ...interface{}) (wait func()) {
+	listen := b.listen(EventFilter{SessionID: sessionID}, callbacks)
+	return func() {
+		_, err := listen()
+		if errors.Is(err, &ErrEventOverflow{}) {
+			b.logger.Println(err)
+		}
+	}
+}
+
+// listen is the same as eachEvent, but only the events that match the filter will be passed to the callbacks.
+// The wait function returns true if the loop is stopped by a callback, the err is why the event stream is closed.
+func (b *Browser) listen(filter EventFilter, callbacks []interface{}) (wait func() (stopped bool, err error)) {
 	cbMap := map[string]reflect.Value{}
 	restores := []func(){}
 
+	sessionID := filter.SessionID
+	if sessionID == "" && filter.TargetID != "" {
+		if page := b.loadCachedPage(filter.TargetID); page != nil {
+			sessionID = page.SessionID
+		}
+	}
+
 	for _, cb := range callbacks {
 		cbVal := reflect.ValueOf(cb)
 		eType := cbVal.Type().In(0)
@@ -357,9 +414,9 @@ func (b *Browser) eachEvent(sessionID proto.TargetSessionID, callbacks ...interf
 	}
 
 	b, cancel := b.WithCancel()
-	messages := b.Event()
+	messages, errOf := b.events()
 
-	return func() {
+	return func() (bool, error) {
 		if messages == nil {
 			panic("can't use wait function twice")
 		}
@@ -373,62 +430,88 @@ func (b *Browser) eachEvent(sessionID proto.TargetSessionID, callbacks ...interf
 		}()
 
 		for msg := range messages {
-			if !(sessionID == "" || msg.SessionID == sessionID) {
+			cbVal, has := cbMap[msg.Method]
+			if !has || !filter.match(b, msg) {
 				continue
 			}
 
-			if cbVal, has := cbMap[msg.Method]; has {
-				e := reflect.New(proto.GetType(msg.Method))
-				msg.Load(e.Interface().(proto.Event))
-				args := []reflect.Value{e}
-				if cbVal.Type().NumIn() == 2 {
-					args = append(args, reflect.ValueOf(sessionID))
-				}
-				res := cbVal.Call(args)
-				if len(res) > 0 {
-					if res[0].Bool() {
-						return
-					}
+			e := reflect.New(cbVal.Type().In(0).Elem())
+			msg.Load(e.Interface().(proto.Event))
+			args := []reflect.Value{e}
+			if cbVal.Type().NumIn() == 2 {
+				args = append(args, reflect.ValueOf(msg.SessionID))
+			}
+			res := cbVal.Call(args)
+			if len(res) > 0 {
+				if res[0].Bool() {
+					return true, nil
 				}
 			}
 		}
+		return false, errOf()
 	}
 }
 
+// EventBuffer returns a clone with the buffer settings for the event subscribers created by it, such as
+// Browser.Event, Browser.EachEvent, and Browser.WaitEvent. By default the buffer size is unlimited,
+// a slow subscriber may use a lot of memory on a page with heavy traffic.
+func (b *Browser) EventBuffer(size int, overflow EventOverflow) *Browser {
+	newObj := *b
+	newObj.eventBuffer = EventBuffer{Size: size, Overflow: overflow}
+	return &newObj
+}
+
+// EventMetrics of the events from the browser, such as how many events are dropped by the EventBuffer
+func (b *Browser) EventMetrics() EventMetrics {
+	return b.event.metrics()
+}
+
 // Event of the browser
 func (b *Browser) Event() <-chan *Message {
-	src := b.event.Subscribe()
+	msgs, _ := b.events()
+	return msgs
+}
+
+// events is the same as Event, after the channel is closed the err returns why it's closed,
+// such as the ErrEventOverflow.
+func (b *Browser) events() (msgs <-chan *Message, err func() error) {
+	src := b.event.subscribe(b.eventBuffer)
 	dst := make(chan *Message)
 	go func() {
-		defer b.event.Unsubscribe(src)
+		defer b.event.unsubscribe(src)
 		defer close(dst)
 		for {
 			select {
 			case <-b.ctx.Done():
 				return
-			case e, ok := <-src:
+			case e, ok := <-src.events:
 				if !ok {
 					return
 				}
 				select {
 				case <-b.ctx.Done():
 					return
-				case dst <- e.(*Message):
+				case dst <- e:
 				}
 			}
 		}
 	}()
-	return dst
+	return dst, src.err
 }
 
 func (b *Browser) initEvents() {
-	b.event = goob.New()
+	b.event = newEventHub()
 
 	go func() {
-		defer b.event.Close()
+		defer b.event.close()
 		for e := range b.client.Event() {
-			b.event.Publish(&Message{
-				SessionID: proto.TargetSessionID(e.SessionID),
+			if e.Method == cdp.EventReconnected {
+				go b.resume()
+				continue
+			}
+
+			b.event.publish(&Message{
+				SessionID: b.sessions.origin(proto.TargetSessionID(e.SessionID)),
 				Method:    e.Method,
 				lock:      &sync.Mutex{},
 				data:      e.Params,
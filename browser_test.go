@@ -8,11 +8,13 @@ import (
 	"os"
 	"os/exec"
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/cdp"
 	"github.com/go-rod/rod/lib/devices"
+	"github.com/go-rod/rod/lib/launcher"
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/go-rod/rod/lib/utils"
 	"github.com/ysmood/gson"
@@ -144,6 +146,173 @@ func (t T) BrowserEventClose() {
 	close(event)
 }
 
+// a browser that receives the events from the channel, all the cdp calls will succeed
+func (t T) newEventBrowser() (*rod.Browser, chan *cdp.Event) {
+	event := make(chan *cdp.Event)
+	c := &MockClient{
+		connect: func() error { return nil },
+		call: func(ctx context.Context, sessionID, method string, params interface{}) ([]byte, error) {
+			return nil, nil
+		},
+		event: event,
+	}
+	b := rod.New().Client(c).Context(t.Context())
+	t.E(b.Connect())
+	return b, event
+}
+
+// publish n events named as "Test.e0", "Test.e1", ... and wait until all of them are published
+func (t T) publishEvents(b *rod.Browser, event chan *cdp.Event, n int) {
+	for i := 0; i < n; i++ {
+		event <- &cdp.Event{Method: fmt.Sprintf("Test.e%d", i)}
+	}
+	for b.EventMetrics().Published < int64(n) {
+		utils.Sleep(0.01)
+	}
+}
+
+func (t T) BrowserEachEventSession() {
+	b, event := t.newEventBrowser()
+	sessions := make(chan proto.TargetSessionID, 1)
+	wait := b.EachEvent(func(e *proto.PageFrameNavigated, id proto.TargetSessionID) bool {
+		sessions <- id
+		return true
+	})
+	go func() {
+		for b.EventMetrics().Subscribers < 1 {
+			utils.Sleep(0.01)
+		}
+		event <- &cdp.Event{SessionID: "s1", Method: "Page.frameNavigated", Params: []byte(`{"frame":{"id":"f"}}`)}
+	}()
+	wait()
+	t.Eq(proto.TargetSessionID("s1"), <-sessions)
+}
+
+func (t T) BrowserEventBuffer() {
+	{ // drop oldest
+		b, event := t.newEventBrowser()
+		msgs := b.EventBuffer(1, rod.EventOverflowDropOldest).Event()
+		t.publishEvents(b, event, 10)
+
+		m := b.EventMetrics()
+		t.Gt(m.Dropped, 0)
+		t.Eq(1, m.Subscribers)
+
+		var last *rod.Message
+		for i := int64(0); i < 10-m.Dropped; i++ {
+			last = <-msgs
+		}
+		t.Eq("Test.e9", last.Method)
+	}
+
+	{ // drop newest
+		b, event := t.newEventBrowser()
+		msgs := b.EventBuffer(1, rod.EventOverflowDropNewest).Event()
+		t.publishEvents(b, event, 10)
+
+		m := b.EventMetrics()
+		t.Gt(m.Dropped, 0)
+		t.Eq("Test.e0", (<-msgs).Method)
+	}
+
+	{ // error
+		b, event := t.newEventBrowser()
+		msgs := b.EventBuffer(1, rod.EventOverflowError).Event()
+		t.publishEvents(b, event, 10)
+
+		t.Eq(int64(1), b.EventMetrics().Overflowed)
+
+		count := 0
+		for range msgs {
+			count++
+		}
+		t.Lt(count, 10)
+	}
+
+	{ // error of the wait functions
+		b, event := t.newEventBrowser()
+		b = b.EventBuffer(1, rod.EventOverflowError)
+		logged := make(chan []interface{}, 1)
+		b.Logger(utils.Log(func(msg ...interface{}) { logged <- msg }))
+		block := make(chan struct{})
+		s := b.Subscribe(rod.EventFilter{}, func(*proto.PageFrameNavigated) { <-block })
+		wait := b.EachEvent(func(*proto.PageFrameNavigated) { <-block })
+		go func() {
+			for b.EventMetrics().Subscribers < 2 {
+				utils.Sleep(0.01)
+			}
+			for i := 0; i < 10; i++ {
+				event <- &cdp.Event{Method: "Page.frameNavigated", Params: []byte(`{"frame":{"id":"f"}}`)}
+			}
+			close(block)
+		}()
+
+		t.Is(s.Err(), &rod.ErrEventOverflow{})
+		t.Is(s.Err(), &rod.ErrEventStreamClosed{})
+		wait()
+		t.Is((<-logged)[0], &rod.ErrEventOverflow{})
+	}
+
+	{ // unlimited
+		b, event := t.newEventBrowser()
+		msgs := b.Event()
+		t.publishEvents(b, event, 10)
+
+		t.Eq(int64(0), b.EventMetrics().Dropped)
+		for i := 0; i < 10; i++ {
+			t.Eq(fmt.Sprintf("Test.e%d", i), (<-msgs).Method)
+		}
+	}
+}
+
+func (t T) BrowserSubscribe() {
+	b, event := t.newEventBrowser()
+
+	frames := make(chan proto.PageFrameID, 10)
+	s := b.Subscribe(rod.EventFilter{SessionID: "s1", FrameID: "f1"}, func(e *proto.PageFrameNavigated) {
+		frames <- e.Frame.ID
+	}, func(e *proto.NetworkRequestWillBeSent) {
+		frames <- e.FrameID
+	})
+
+	event <- &cdp.Event{SessionID: "s2", Method: "Page.frameNavigated", Params: []byte(`{"frame":{"id":"f1"}}`)}
+	event <- &cdp.Event{SessionID: "s1", Method: "Page.frameNavigated", Params: []byte(`{"frame":{"id":"f2"}}`)}
+	event <- &cdp.Event{SessionID: "s1", Method: "Page.frameNavigated", Params: []byte(`{"frame":{"id":"f1"}}`)}
+	event <- &cdp.Event{SessionID: "s1", Method: "Network.requestWillBeSent", Params: []byte(`{"frameId":"f1"}`)}
+	t.Eq(proto.PageFrameID("f1"), <-frames)
+	t.Eq(proto.PageFrameID("f1"), <-frames)
+
+	s.Unsubscribe()
+	t.False(s.Stopped())
+	t.Len(frames, 0)
+
+	for b.EventMetrics().Subscribers > 0 {
+		utils.Sleep(0.01)
+	}
+	go func() {
+		for b.EventMetrics().Subscribers == 0 {
+			utils.Sleep(0.01)
+		}
+		event <- &cdp.Event{Method: "Target.targetCreated", Params: []byte(`{"targetInfo":{"targetId":"t1","url":"a"}}`)}
+		event <- &cdp.Event{Method: "Target.targetCreated", Params: []byte(`{"targetInfo":{"targetId":"t2","url":"b"}}`)}
+		event <- &cdp.Event{Method: "Target.targetCreated", Params: []byte(`{"targetInfo":{"targetId":"t2","url":"c"}}`)}
+	}()
+	var e proto.TargetTargetCreated
+	b.MustWaitEventMatch(rod.EventFilter{TargetID: "t2"}, time.Minute, func(ee *proto.TargetTargetCreated) bool {
+		e = *ee
+		return ee.TargetInfo.URL == "c"
+	})
+	t.Eq("c", e.TargetInfo.URL)
+
+	err := b.WaitEventMatch(rod.EventFilter{Match: func(*rod.Message) bool { return false }}, time.Millisecond,
+		func(*proto.TargetTargetCreated) bool { return true })
+	t.Is(err, context.DeadlineExceeded)
+
+	close(event)
+	err = b.WaitEventMatch(rod.EventFilter{}, time.Minute, func(*proto.TargetTargetCreated) bool { return true })
+	t.Is(err, &rod.ErrEventStreamClosed{})
+}
+
 func (t T) BrowserWaitEvent() {
 	t.NotNil(t.browser.Context(t.Context()).Event())
 
@@ -177,6 +346,76 @@ func (t T) BrowserCrash() {
 	t.Is(err, cdp.ErrConnClosed)
 }
 
+func (t T) WatchdogPageCrash() {
+	browser := rod.New().Context(t.Context()).Watchdog(nil).MustConnect()
+	defer browser.MustClose()
+
+	page := browser.MustPage(t.blank())
+	js := `() => new Promise(r => setTimeout(r, 10000))`
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := page.Eval(js)
+		errs <- err
+	}()
+
+	utils.Sleep(0.2)
+
+	_ = proto.PageCrash{}.Call(page)
+
+	t.Is(<-errs, &rod.ErrPageCrashed{})
+
+	_, err := page.Eval(js)
+	t.Is(err, &rod.ErrPageCrashed{})
+
+	t.E(page.Navigate(t.blank()))
+}
+
+func (t T) WatchdogBrowserExited() {
+	l := launcher.New()
+	browser := rod.New().Context(t.Context()).Watchdog(&rod.WatchdogOptions{Launcher: l}).MustConnect()
+
+	page := browser.MustPage(t.blank())
+	js := `() => new Promise(r => setTimeout(r, 10000))`
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := page.Eval(js)
+		errs <- err
+	}()
+
+	utils.Sleep(0.2)
+
+	l.Kill()
+
+	t.Is(<-errs, &rod.ErrBrowserExited{})
+
+	_, err := page.Eval(js)
+	t.Is(err, &rod.ErrBrowserExited{})
+}
+
+func (t T) WatchdogRelaunch() {
+	l := launcher.New()
+	browser := rod.New().Context(t.Context()).Watchdog(&rod.WatchdogOptions{Launcher: l, Relaunch: true}).MustConnect()
+	defer browser.MustClose()
+
+	page := browser.MustPage(t.srcFile("fixtures/click.html")).MustWaitLoad()
+
+	wait := browser.WaitEvent(&rod.BrowserReconnected{})
+	l.Kill()
+	wait()
+
+	t.Has(page.MustEval(`() => location.href`).String(), "click.html")
+
+	// the relaunched browser uses a fresh user-data-dir
+	dir, _ := l.Get("user-data-dir")
+	cmd, err := proto.BrowserGetBrowserCommandLine{}.Call(browser)
+	t.E(err)
+	for _, arg := range cmd.Arguments {
+		t.Neq(arg, "--user-data-dir="+dir)
+	}
+}
+
 func (t T) BrowserCall() {
 	v, err := proto.BrowserGetVersion{}.Call(t.browser)
 	t.E(err)
@@ -403,3 +642,73 @@ func (t T) StreamReader() {
 	_, err = r.Read(nil)
 	t.Err(err)
 }
+
+func (t T) BrowserReconnect() {
+	s := t.Serve().Route("/", ".html", `<body>ok</body>`)
+
+	l := launcher.New()
+	defer l.Kill()
+
+	ws := &droppableWebSocket{}
+	client := cdp.New(l.MustLaunch()).Websocket(ws).Reconnect(func() utils.Sleeper {
+		return utils.CountSleeper(10)
+	})
+
+	b := rod.New().Client(client).MustConnect()
+	defer b.MustClose()
+
+	p := b.MustPage(s.URL()).MustWaitLoad()
+
+	var e rod.BrowserReconnected
+	wait := b.WaitEvent(&e)
+	ws.drop()
+	wait()
+
+	t.Eq([]proto.TargetTargetID{p.TargetID}, e.Pages)
+
+	// the page still works with its previous session
+	t.Eq("ok", p.MustElement("body").MustText())
+	t.Eq("ok", p.MustReload().MustWaitLoad().MustElement("body").MustText())
+}
+
+// droppableWebSocket can drop the current connection to simulate a network failure
+type droppableWebSocket struct {
+	sync.Mutex
+	ws     *cdp.WebSocket
+	cancel func()
+}
+
+func (d *droppableWebSocket) Connect(ctx context.Context, url string, header http.Header) error {
+	ctx, cancel := context.WithCancel(ctx)
+	ws := &cdp.WebSocket{}
+	err := ws.Connect(ctx, url, header)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	d.ws, d.cancel = ws, cancel
+	return nil
+}
+
+func (d *droppableWebSocket) Send(b []byte) error {
+	d.Lock()
+	ws := d.ws
+	d.Unlock()
+	return ws.Send(b)
+}
+
+func (d *droppableWebSocket) Read() ([]byte, error) {
+	d.Lock()
+	ws := d.ws
+	d.Unlock()
+	return ws.Read()
+}
+
+func (d *droppableWebSocket) drop() {
+	d.Lock()
+	defer d.Unlock()
+	d.cancel()
+}
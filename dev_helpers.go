@@ -42,11 +42,29 @@ const (
 
 	// TraceTypeInput type
 	TraceTypeInput TraceType = "input"
+
+	// TraceTypeNavigate type
+	TraceTypeNavigate TraceType = "navigate"
 )
 
+// MonitorOptions for Browser.ServeMonitor
+type MonitorOptions struct {
+	// Control allows the operator to forward the mouse and keyboard input to the pages via the page view.
+	// Anyone who can reach the monitor can then drive the pages, so only enable it on a trusted network.
+	Control bool
+}
+
 // ServeMonitor starts the monitor server.
 // The reason why not to use "chrome://inspect/#devices" is one target cannot be driven by multiple controllers.
-func (b *Browser) ServeMonitor(host string) string {
+// The page view streams the screencast of the page, if the MonitorOptions.Control is enabled an operator can
+// forward the mouse and keyboard input to the page, check Page.WaitOperator for how to hand the page back to the script.
+// The requests from the pages of other origins are rejected.
+func (b *Browser) ServeMonitor(host string, opts ...*MonitorOptions) string {
+	opt := &MonitorOptions{}
+	if len(opts) > 0 && opts[0] != nil {
+		opt = opts[0]
+	}
+
 	url, mux, close := serve(host)
 	go func() {
 		<-b.ctx.Done()
@@ -88,6 +106,8 @@ func (b *Browser) ServeMonitor(host string) string {
 		w.Header().Add("Content-Type", "image/png;")
 		utils.E(w.Write(p.MustScreenshot()))
 	})
+	mux.HandleFunc("/ws/page/", sameOrigin(b.serveLiveView(opt.Control)))
+	mux.HandleFunc("/api/operator/", sameOrigin(b.serveOperator))
 
 	return url
 }
@@ -131,8 +151,12 @@ func (p *Page) Overlay(left, top, width, height float64, msg string) (remove fun
 }
 
 func (p *Page) tryTrace(typ TraceType, msg ...interface{}) func() {
+	end := p.traceAction(func() *TraceEvent {
+		return &TraceEvent{Type: typ, Message: traceMessage(msg)}
+	})
+
 	if !p.browser.trace {
-		return func() {}
+		return end
 	}
 
 	msg = append([]interface{}{typ}, msg...)
@@ -140,7 +164,11 @@ func (p *Page) tryTrace(typ TraceType, msg ...interface{}) func() {
 
 	p.browser.logger.Println(msg...)
 
-	return p.Overlay(0, 0, 500, 0, fmt.Sprint(msg))
+	remove := p.Overlay(0, 0, 500, 0, fmt.Sprint(msg))
+	return func() {
+		remove()
+		end()
+	}
 }
 
 func (p *Page) tryTraceQuery(opts *EvalOptions) func() {
@@ -211,8 +239,12 @@ func (el *Element) Overlay(msg string) (removeOverlay func()) {
 }
 
 func (el *Element) tryTrace(typ TraceType, msg ...interface{}) func() {
+	end := el.page.traceAction(func() *TraceEvent {
+		return &TraceEvent{Type: typ, Message: traceMessage(msg), Element: el.String()}
+	})
+
 	if !el.page.browser.trace {
-		return func() {}
+		return end
 	}
 
 	msg = append([]interface{}{typ}, msg...)
@@ -220,7 +252,11 @@ func (el *Element) tryTrace(typ TraceType, msg ...interface{}) func() {
 
 	el.page.browser.logger.Println(msg...)
 
-	return el.Overlay(fmt.Sprint(msg))
+	remove := el.Overlay(fmt.Sprint(msg))
+	return func() {
+		remove()
+		end()
+	}
 }
 
 func (m *Mouse) initMouseTracer() {
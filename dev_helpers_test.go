@@ -1,9 +1,14 @@
 package rod_test
 
 import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/cdp"
 	"github.com/go-rod/rod/lib/defaults"
 	"github.com/go-rod/rod/lib/js"
 	"github.com/go-rod/rod/lib/launcher"
@@ -35,6 +40,122 @@ func (t T) Monitor() {
 	t.Eq(-32602, gson.New(res.Body).Get("code").Int())
 }
 
+func (t T) MonitorLiveView() {
+	b := rod.New().MustConnect()
+	defer b.MustClose()
+	p := b.MustPage(t.srcFile("fixtures/input.html")).MustWaitLoad()
+
+	b, cancel := b.WithCancel()
+	defer cancel()
+	host := b.Context(t.Context()).ServeMonitor("", &rod.MonitorOptions{Control: true})
+
+	ws := &cdp.WebSocket{}
+	t.E(ws.Connect(t.Context(), "ws"+host[4:]+"/ws/page/"+string(p.TargetID), nil))
+
+	msg, err := ws.Read()
+	t.E(err)
+	t.True(gson.New(msg).Get("enabled").Bool())
+
+	msg, err = ws.Read()
+	t.E(err)
+	t.Eq("frame", gson.New(msg).Get("type").Str())
+
+	box := p.MustElement("[type=text]").MustShape().Box()
+	send := func(e map[string]interface{}) { t.E(ws.Send(utils.MustToJSONBytes(e))) }
+	send(map[string]interface{}{"type": "mousedown", "x": box.X + 1, "y": box.Y + 1, "clickCount": 1})
+	send(map[string]interface{}{"type": "mouseup", "x": box.X + 1, "y": box.Y + 1, "clickCount": 1})
+	send(map[string]interface{}{"type": "keydown", "key": "a"})
+	send(map[string]interface{}{"type": "keyup", "key": "a"})
+	send(map[string]interface{}{"type": "keydown", "key": "Shift"})
+	send(map[string]interface{}{"type": "keyup", "key": "Shift"})
+	send(map[string]interface{}{"type": "keydown", "key": "UnknownKey"})
+	send(map[string]interface{}{"type": "text", "text": "b"})
+	send(map[string]interface{}{"type": "wheel", "deltaY": 10})
+	send(map[string]interface{}{"type": "mousemove", "x": 1, "y": 1})
+	send(map[string]interface{}{"type": "unknown"})
+
+	p.MustWait(`() => document.querySelector('[type=text]').value === 'ab'`)
+
+	for {
+		msg, err = ws.Read()
+		t.E(err)
+		if gson.New(msg).Get("type").Str() == "error" {
+			t.Has(gson.New(msg).Get("error").Str(), "unknown input type")
+			break
+		}
+	}
+
+	res := t.Req("", host+"/ws/page/"+string(p.TargetID))
+	t.Eq(400, res.StatusCode)
+}
+
+func (t T) MonitorLiveViewNoControl() {
+	b := rod.New().MustConnect()
+	defer b.MustClose()
+	p := b.MustPage(t.blank())
+
+	b, cancel := b.WithCancel()
+	defer cancel()
+	host := b.Context(t.Context()).ServeMonitor("")
+	u := "ws" + host[4:] + "/ws/page/" + string(p.TargetID)
+
+	ws := &cdp.WebSocket{}
+	t.Err(ws.Connect(t.Context(), u, http.Header{"Origin": {"http://example.com"}}))
+
+	ws = &cdp.WebSocket{}
+	t.E(ws.Connect(t.Context(), u, nil))
+
+	msg, err := ws.Read()
+	t.E(err)
+	t.False(gson.New(msg).Get("enabled").Bool())
+
+	t.E(ws.Send([]byte(`{"type":"text","text":"a"}`)))
+	for {
+		msg, err = ws.Read()
+		t.E(err)
+		if gson.New(msg).Get("type").Str() == "error" {
+			t.Has(gson.New(msg).Get("error").Str(), "the control is disabled")
+			break
+		}
+	}
+}
+
+func (t T) MonitorOperator() {
+	b := rod.New().MustConnect()
+	defer b.MustClose()
+	p := b.MustPage(t.blank())
+
+	b, cancel := b.WithCancel()
+	defer cancel()
+	host := b.Context(t.Context()).ServeMonitor("")
+	api := host + "/api/operator/" + string(p.TargetID)
+
+	t.False(gson.New(t.Req("", api).Bytes()).Get("waiting").Bool())
+
+	done := make(chan error)
+	go func() { done <- p.WaitOperator("solve the captcha") }()
+
+	for !gson.New(t.Req("", api).Bytes()).Get("waiting").Bool() {
+		utils.Sleep(0.01)
+	}
+	t.Eq("solve the captcha", gson.New(t.Req("", api).Bytes()).Get("request.message").Str())
+
+	req, err := http.NewRequest(http.MethodPost, api, nil)
+	t.E(err)
+	req.Header.Set("Origin", "http://example.com")
+	res, err := http.DefaultClient.Do(req)
+	t.E(err)
+	t.E(res.Body.Close())
+	t.Eq(http.StatusForbidden, res.StatusCode)
+
+	t.False(gson.New(t.Req("POST", api).Bytes()).Get("waiting").Bool())
+	t.E(<-done)
+
+	ctx, cancelWait := context.WithCancel(t.Context())
+	cancelWait()
+	t.Err(p.Context(ctx).WaitOperator(""))
+}
+
 func (t T) MonitorErr() {
 	l := launcher.New()
 	u := l.MustLaunch()
@@ -73,6 +194,55 @@ func (t T) Trace() {
 	_ = p.Mouse.Move(10, 10, 1)
 }
 
+func (t T) TraceLog() {
+	l := rod.NewTraceLog()
+	l.Screenshots = true
+	t.browser.TraceLog(l)
+	defer t.browser.TraceLog(nil)
+
+	p := t.page.MustNavigate(t.srcFile("fixtures/click.html")).MustWaitLoad()
+	p.MustElement("button").MustClick()
+
+	find := func(typ rod.TraceType, msg string) rod.TraceEvent {
+		for _, e := range l.Events() {
+			if e.Type == typ && strings.Contains(e.Message, msg) {
+				return e
+			}
+		}
+		panic("not found: " + msg)
+	}
+
+	nav := find(rod.TraceTypeNavigate, "click.html")
+	t.Eq(p.TargetID, nav.PageID)
+	t.Gt(len(nav.Before), 0)
+	t.Gt(len(nav.After), 0)
+	t.Gte(nav.Duration(), time.Duration(0))
+
+	query := find(rod.TraceTypeQuery, "element")
+	t.Has(query.Selector, "button")
+
+	click := find(rod.TraceTypeInput, "left click")
+	t.Has(click.Element, "button")
+	t.False(click.End.Before(click.Start))
+
+	buf := bytes.NewBuffer(nil)
+	t.E(l.WriteJSON(buf))
+	t.Eq(string(rod.TraceTypeNavigate), gson.New(buf.Bytes()).Get("0.type").Str())
+
+	buf = bytes.NewBuffer(nil)
+	t.E(l.WriteChromeTrace(buf))
+	phases := map[string]int{}
+	for _, e := range gson.New(buf.Bytes()).Get("traceEvents").Arr() {
+		phases[e.Get("ph").Str()]++
+	}
+	t.Gt(phases["M"], 1)
+	t.Gt(phases["X"], 2)
+	t.Gt(phases["O"], 2)
+
+	l.Reset()
+	t.Len(l.Events(), 0)
+}
+
 func (t T) TraceLogs() {
 	t.browser.Logger(utils.LoggerQuiet)
 	t.browser.Trace(true)
@@ -183,3 +183,99 @@ func (e *ErrNoPointerEvents) Unwrap() error {
 func (e *ErrNoPointerEvents) Is(err error) bool {
 	return reflect.TypeOf(e) == reflect.TypeOf(err)
 }
+
+// ErrEventStreamClosed error, such as the browser is disconnected while waiting for an event
+type ErrEventStreamClosed struct {
+}
+
+// Error ...
+func (e *ErrEventStreamClosed) Error() string {
+	return "the event stream is closed"
+}
+
+// Is interface
+func (e *ErrEventStreamClosed) Is(err error) bool {
+	return reflect.TypeOf(e) == reflect.TypeOf(err)
+}
+
+// ErrEventOverflow error, the buffer of the event subscriber is full with the EventOverflowError policy
+type ErrEventOverflow struct {
+	Size int
+}
+
+// Error ...
+func (e *ErrEventOverflow) Error() string {
+	return fmt.Sprintf("the event buffer overflowed, size: %d", e.Size)
+}
+
+// Unwrap ...
+func (e *ErrEventOverflow) Unwrap() error {
+	return &ErrEventStreamClosed{}
+}
+
+// Is interface
+func (e *ErrEventOverflow) Is(err error) bool {
+	return reflect.TypeOf(e) == reflect.TypeOf(err)
+}
+
+// ErrScreenshotMismatch error
+type ErrScreenshotMismatch struct {
+	*VisualResult
+}
+
+// Error ...
+func (e *ErrScreenshotMismatch) Error() string {
+	return fmt.Sprintf("screenshot doesn't match the baseline %s, %.2f%% pixels are different, check the diff: %s",
+		e.Baseline, e.DiffRatio*100, e.Diff)
+}
+
+// Is interface
+func (e *ErrScreenshotMismatch) Is(err error) bool {
+	return reflect.TypeOf(e) == reflect.TypeOf(err)
+}
+
+// ErrStitchTooLarge error, the canvas of the Page.ScreenshotStitched exceeds the StitchOptions.MaxBytes
+type ErrStitchTooLarge struct {
+	Width, Height int
+	MaxBytes      int
+}
+
+// Error ...
+func (e *ErrStitchTooLarge) Error() string {
+	return fmt.Sprintf("the stitched image of %dx%d pixels exceeds %d bytes, lower the StitchOptions.MaxHeight",
+		e.Width, e.Height, e.MaxBytes)
+}
+
+// Is interface
+func (e *ErrStitchTooLarge) Is(err error) bool {
+	return reflect.TypeOf(e) == reflect.TypeOf(err)
+}
+
+// ErrPageCrashed error, the renderer process of the page crashed. Check Browser.Watchdog for details.
+type ErrPageCrashed struct {
+	TargetID proto.TargetTargetID
+}
+
+// Error ...
+func (e *ErrPageCrashed) Error() string {
+	return fmt.Sprintf("page crashed: %s", e.TargetID)
+}
+
+// Is interface
+func (e *ErrPageCrashed) Is(err error) bool {
+	return reflect.TypeOf(e) == reflect.TypeOf(err)
+}
+
+// ErrBrowserExited error, the browser process exited. Check Browser.Watchdog for details.
+type ErrBrowserExited struct {
+}
+
+// Error ...
+func (e *ErrBrowserExited) Error() string {
+	return "the browser process exited"
+}
+
+// Is interface
+func (e *ErrBrowserExited) Is(err error) bool {
+	return reflect.TypeOf(e) == reflect.TypeOf(err)
+}
@@ -0,0 +1,237 @@
+package rod
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// EventOverflow is the policy when the buffer of an event subscriber is full
+type EventOverflow int
+
+const (
+	// EventOverflowBlock blocks the publishing until the subscriber has space in its buffer,
+	// it will slow down all the other subscribers and the cdp client.
+	EventOverflowBlock EventOverflow = iota
+
+	// EventOverflowDropOldest drops the oldest event in the buffer to make space for the new one
+	EventOverflowDropOldest
+
+	// EventOverflowDropNewest drops the new event
+	EventOverflowDropNewest
+
+	// EventOverflowError closes the subscription with ErrEventOverflow, the events in the buffer will still be
+	// delivered. The Browser.Subscribe and Browser.WaitEventMatch return the error, the wait functions of the
+	// Browser.EachEvent and Browser.WaitEvent panic with it, the channel of the Browser.Event is simply closed.
+	EventOverflowError
+)
+
+// EventBuffer settings for the event subscribers
+type EventBuffer struct {
+	// Size of the buffer, zero means unlimited
+	Size int
+
+	// Overflow policy when the buffer is full
+	Overflow EventOverflow
+}
+
+// EventMetrics of the events from the browser
+type EventMetrics struct {
+	// Published events from the cdp client
+	Published int64
+
+	// Dropped events by the EventOverflowDropOldest or EventOverflowDropNewest
+	Dropped int64
+
+	// Overflowed subscriptions that are closed by the EventOverflowError
+	Overflowed int64
+
+	// Subscribers currently subscribing
+	Subscribers int
+}
+
+// eventHub dispatches the events to each subscriber via its own buffer
+type eventHub struct {
+	lock   *sync.Mutex
+	subs   map[*eventSubscriber]struct{}
+	closed bool
+
+	published  int64
+	dropped    int64
+	overflowed int64
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{
+		lock: &sync.Mutex{},
+		subs: map[*eventSubscriber]struct{}{},
+	}
+}
+
+type eventSubscriber struct {
+	hub    *eventHub
+	buffer EventBuffer
+
+	lock       *sync.Mutex
+	cond       *sync.Cond
+	queue      []*Message
+	closed     bool
+	overflowed bool
+
+	events chan *Message
+	done   chan struct{}
+}
+
+func (h *eventHub) subscribe(buffer EventBuffer) *eventSubscriber {
+	s := &eventSubscriber{
+		hub:    h,
+		buffer: buffer,
+		lock:   &sync.Mutex{},
+		events: make(chan *Message),
+		done:   make(chan struct{}),
+	}
+	s.cond = sync.NewCond(s.lock)
+
+	h.lock.Lock()
+	if h.closed {
+		s.closed = true
+	} else {
+		h.subs[s] = struct{}{}
+	}
+	h.lock.Unlock()
+
+	go s.deliver()
+
+	return s
+}
+
+func (h *eventHub) unsubscribe(s *eventSubscriber) {
+	h.lock.Lock()
+	delete(h.subs, s)
+	h.lock.Unlock()
+
+	s.close()
+	close(s.done)
+}
+
+func (h *eventHub) publish(msg *Message) {
+	atomic.AddInt64(&h.published, 1)
+
+	// the lock of the hub is not held while pushing, so that a blocked subscriber can still unsubscribe
+	h.lock.Lock()
+	subs := make([]*eventSubscriber, 0, len(h.subs))
+	for s := range h.subs {
+		subs = append(subs, s)
+	}
+	h.lock.Unlock()
+
+	for _, s := range subs {
+		s.push(msg)
+	}
+}
+
+func (h *eventHub) close() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.closed = true
+	for s := range h.subs {
+		s.close()
+	}
+	h.subs = map[*eventSubscriber]struct{}{}
+}
+
+func (h *eventHub) metrics() EventMetrics {
+	h.lock.Lock()
+	n := len(h.subs)
+	h.lock.Unlock()
+
+	return EventMetrics{
+		Published:   atomic.LoadInt64(&h.published),
+		Dropped:     atomic.LoadInt64(&h.dropped),
+		Overflowed:  atomic.LoadInt64(&h.overflowed),
+		Subscribers: n,
+	}
+}
+
+func (s *eventSubscriber) push(msg *Message) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for !s.closed && s.buffer.Size > 0 && len(s.queue) >= s.buffer.Size {
+		switch s.buffer.Overflow {
+		case EventOverflowDropOldest:
+			s.queue = s.queue[1:]
+			atomic.AddInt64(&s.hub.dropped, 1)
+		case EventOverflowDropNewest:
+			atomic.AddInt64(&s.hub.dropped, 1)
+			return
+		case EventOverflowError:
+			atomic.AddInt64(&s.hub.overflowed, 1)
+			s.closed = true
+			s.overflowed = true
+			s.cond.Broadcast()
+			go s.hub.remove(s)
+			return
+		default:
+			s.cond.Wait()
+		}
+	}
+
+	if s.closed {
+		return
+	}
+
+	s.queue = append(s.queue, msg)
+	s.cond.Broadcast()
+}
+
+func (h *eventHub) remove(s *eventSubscriber) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	delete(h.subs, s)
+}
+
+func (s *eventSubscriber) close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.closed = true
+	s.cond.Broadcast()
+}
+
+// err returns the ErrEventOverflow if the subscriber is closed by the EventOverflowError
+func (s *eventSubscriber) err() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.overflowed {
+		return &ErrEventOverflow{Size: s.buffer.Size}
+	}
+	return nil
+}
+
+// deliver the buffered events to the events channel, the channel will be closed after
+// the subscriber is closed and the buffer is drained.
+func (s *eventSubscriber) deliver() {
+	defer close(s.events)
+
+	for {
+		s.lock.Lock()
+		for len(s.queue) == 0 && !s.closed {
+			s.cond.Wait()
+		}
+		if len(s.queue) == 0 {
+			s.lock.Unlock()
+			return
+		}
+		msg := s.queue[0]
+		s.queue = s.queue[1:]
+		s.cond.Broadcast()
+		s.lock.Unlock()
+
+		select {
+		case <-s.done:
+			return
+		case s.events <- msg:
+		}
+	}
+}
@@ -3,7 +3,6 @@ module github.com/go-rod/rod
 go 1.16
 
 require (
-	github.com/ysmood/goob v0.3.0
 	github.com/ysmood/got v0.9.3
 	github.com/ysmood/gotrace v0.2.0
 	github.com/ysmood/gson v0.6.3
@@ -0,0 +1,514 @@
+// This file implements the HAR 1.2 format to record and replay the network traffic.
+// Spec: http://www.softwareishard.com/blog/har-12-spec/
+
+package rod
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// HAR is the root object of a HTTP Archive
+type HAR struct {
+	Log *HARLog `json:"log"`
+}
+
+// HARLog of the archive
+type HARLog struct {
+	Version string      `json:"version"`
+	Creator *HARCreator `json:"creator"`
+	Entries []*HAREntry `json:"entries"`
+}
+
+// HARCreator of the archive
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// HAREntry for each request
+type HAREntry struct {
+	StartedDateTime time.Time    `json:"startedDateTime"`
+	Time            float64      `json:"time"`
+	Request         *HARRequest  `json:"request"`
+	Response        *HARResponse `json:"response"`
+	Cache           struct{}     `json:"cache"`
+	Timings         *HARTimings  `json:"timings"`
+	ServerIPAddress string       `json:"serverIPAddress,omitempty"`
+
+	ResourceType proto.NetworkResourceType `json:"_resourceType,omitempty"`
+	Error        string                    `json:"_error,omitempty"`
+}
+
+// HARRequest data
+type HARRequest struct {
+	Method      string          `json:"method"`
+	URL         string          `json:"url"`
+	HTTPVersion string          `json:"httpVersion"`
+	Cookies     []*HARNameValue `json:"cookies"`
+	Headers     []*HARNameValue `json:"headers"`
+	QueryString []*HARNameValue `json:"queryString"`
+	PostData    *HARPostData    `json:"postData,omitempty"`
+	HeadersSize int             `json:"headersSize"`
+	BodySize    int             `json:"bodySize"`
+}
+
+// HARResponse data
+type HARResponse struct {
+	Status      int             `json:"status"`
+	StatusText  string          `json:"statusText"`
+	HTTPVersion string          `json:"httpVersion"`
+	Cookies     []*HARNameValue `json:"cookies"`
+	Headers     []*HARNameValue `json:"headers"`
+	Content     *HARContent     `json:"content"`
+	RedirectURL string          `json:"redirectURL"`
+	HeadersSize int             `json:"headersSize"`
+	BodySize    int             `json:"bodySize"`
+}
+
+// HARNameValue pair, used for headers, cookies and query strings
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARPostData of the request
+type HARPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// HARContent of the response body
+type HARContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// HARTimings of the request in milliseconds, -1 means not applicable
+type HARTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+// Body decodes the content text
+func (c *HARContent) Body() []byte {
+	if c.Encoding == "base64" {
+		b, _ := base64.StdEncoding.DecodeString(c.Text)
+		return b
+	}
+	return []byte(c.Text)
+}
+
+// ReadHAR from a reader
+func ReadHAR(r io.Reader) (*HAR, error) {
+	har := &HAR{}
+	err := json.NewDecoder(r).Decode(har)
+	if err != nil {
+		return nil, err
+	}
+	if har.Log == nil {
+		har.Log = &HARLog{}
+	}
+	return har, nil
+}
+
+// RecordHAR records the network traffic of the page until the returned stop is called,
+// then the archive will be written to w as HAR 1.2 json.
+func (p *Page) RecordHAR(w io.Writer) (stop func() error) {
+	return p.browser.Context(p.ctx).recordHAR(p.SessionID, w)
+}
+
+// RecordHAR is similar to Page.RecordHAR, but records the traffic of all the pages of the browser.
+// Only the pages that already exist when it's called will be recorded.
+func (b *Browser) RecordHAR(w io.Writer) (stop func() error) {
+	pages, _ := b.Pages()
+	restores := []func(){}
+	for _, p := range pages {
+		restores = append(restores, p.EnableDomain(&proto.NetworkEnable{}))
+	}
+
+	record := b.recordHAR("", w)
+
+	return func() error {
+		defer func() {
+			for _, restore := range restores {
+				restore()
+			}
+		}()
+		return record()
+	}
+}
+
+func (b *Browser) recordHAR(sessionID proto.TargetSessionID, w io.Writer) func() error {
+	rec := &harRecorder{
+		browser: b,
+		lock:    &sync.Mutex{},
+		wg:      &sync.WaitGroup{},
+		entries: map[proto.NetworkRequestID]*harRecord{},
+	}
+
+	b, cancel := b.WithCancel()
+
+	wait := b.eachEvent(sessionID, rec.request, rec.response, rec.finished, rec.failed)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		wait()
+	}()
+
+	return func() error {
+		cancel()
+		<-done
+		rec.wg.Wait()
+
+		return WriteHAR(w, rec.har())
+	}
+}
+
+type harRecorder struct {
+	browser *Browser
+	lock    *sync.Mutex
+	wg      *sync.WaitGroup
+	list    []*harRecord
+	entries map[proto.NetworkRequestID]*harRecord
+}
+
+type harRecord struct {
+	entry     *HAREntry
+	timestamp proto.MonotonicTime
+	timing    *proto.NetworkResourceTiming
+}
+
+func (rec *harRecorder) request(e *proto.NetworkRequestWillBeSent) {
+	rec.lock.Lock()
+	defer rec.lock.Unlock()
+
+	// the redirect response ends the previous entry with the same request id
+	if r, has := rec.entries[e.RequestID]; has && e.RedirectResponse != nil {
+		r.setResponse(e.RedirectResponse)
+		r.end(e.Timestamp)
+	}
+
+	req := &HARRequest{
+		Method:      e.Request.Method,
+		URL:         e.Request.URL + e.Request.URLFragment,
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []*HARNameValue{},
+		Headers:     harHeaders(e.Request.Headers),
+		QueryString: harQuery(e.Request.URL),
+		HeadersSize: -1,
+		BodySize:    len(e.Request.PostData),
+	}
+	if e.Request.HasPostData {
+		req.PostData = &HARPostData{
+			MimeType: harHeader(e.Request.Headers, "Content-Type"),
+			Text:     e.Request.PostData,
+		}
+	}
+
+	r := &harRecord{
+		entry: &HAREntry{
+			StartedDateTime: e.WallTime.Time(),
+			Request:         req,
+			Response: &HARResponse{
+				Cookies: []*HARNameValue{},
+				Headers: []*HARNameValue{},
+				Content: &HARContent{},
+			},
+			Timings:      &HARTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1},
+			ResourceType: e.Type,
+		},
+		timestamp: e.Timestamp,
+	}
+
+	rec.entries[e.RequestID] = r
+	rec.list = append(rec.list, r)
+}
+
+func (rec *harRecorder) response(e *proto.NetworkResponseReceived) {
+	rec.lock.Lock()
+	defer rec.lock.Unlock()
+
+	if r, has := rec.entries[e.RequestID]; has {
+		r.setResponse(e.Response)
+	}
+}
+
+func (rec *harRecorder) finished(e *proto.NetworkLoadingFinished, sessionID proto.TargetSessionID) {
+	rec.lock.Lock()
+	defer rec.lock.Unlock()
+
+	r, has := rec.entries[e.RequestID]
+	if !has {
+		return
+	}
+	delete(rec.entries, e.RequestID)
+	r.end(e.Timestamp)
+
+	// retrieve the body in background to prevent blocking the event loop
+	rec.wg.Add(1)
+	go func() {
+		defer rec.wg.Done()
+
+		page := rec.browser.PageFromSession(sessionID)
+		res, err := proto.NetworkGetResponseBody{RequestID: e.RequestID}.Call(page)
+		if err != nil {
+			return
+		}
+
+		rec.lock.Lock()
+		defer rec.lock.Unlock()
+
+		content := r.entry.Response.Content
+		content.Text = res.Body
+		if res.Base64Encoded {
+			content.Encoding = "base64"
+		}
+		content.Size = len(content.Body())
+		r.entry.Response.BodySize = int(e.EncodedDataLength)
+	}()
+}
+
+func (rec *harRecorder) failed(e *proto.NetworkLoadingFailed) {
+	rec.lock.Lock()
+	defer rec.lock.Unlock()
+
+	if r, has := rec.entries[e.RequestID]; has {
+		delete(rec.entries, e.RequestID)
+		r.entry.Error = e.ErrorText
+		r.end(e.Timestamp)
+	}
+}
+
+func (rec *harRecorder) har() *HAR {
+	rec.lock.Lock()
+	defer rec.lock.Unlock()
+
+	entries := []*HAREntry{}
+	for _, r := range rec.list {
+		entries = append(entries, r.entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartedDateTime.Before(entries[j].StartedDateTime)
+	})
+
+	return &HAR{Log: &HARLog{
+		Version: "1.2",
+		Creator: &HARCreator{Name: "rod", Version: "0"},
+		Entries: entries,
+	}}
+}
+
+func (r *harRecord) setResponse(res *proto.NetworkResponse) {
+	r.timing = res.Timing
+	r.entry.ServerIPAddress = res.RemoteIPAddress
+	r.entry.Response = &HARResponse{
+		Status:      res.Status,
+		StatusText:  res.StatusText,
+		HTTPVersion: harHTTPVersion(res.Protocol),
+		Cookies:     []*HARNameValue{},
+		Headers:     harHeaders(res.Headers),
+		Content: &HARContent{
+			MimeType: res.MIMEType,
+		},
+		RedirectURL: harHeader(res.Headers, "Location"),
+		HeadersSize: -1,
+		BodySize:    -1,
+	}
+	r.entry.Request.HTTPVersion = r.entry.Response.HTTPVersion
+}
+
+func (r *harRecord) end(timestamp proto.MonotonicTime) {
+	total := ms(timestamp - r.timestamp)
+	r.entry.Time = total
+
+	t := r.entry.Timings
+	if r.timing == nil {
+		t.Wait = total
+		return
+	}
+
+	timing := r.timing
+	if timing.DNSStart >= 0 {
+		t.DNS = timing.DNSEnd - timing.DNSStart
+	}
+	if timing.ConnectStart >= 0 {
+		t.Connect = timing.ConnectEnd - timing.ConnectStart
+	}
+	if timing.SslStart >= 0 {
+		t.SSL = timing.SslEnd - timing.SslStart
+	}
+	t.Blocked = ms(proto.MonotonicTime(timing.RequestTime) - r.timestamp)
+	t.Send = timing.SendEnd - timing.SendStart
+	t.Wait = timing.ReceiveHeadersEnd - timing.SendEnd
+	t.Receive = total - t.Blocked - timing.ReceiveHeadersEnd
+	if t.Receive < 0 {
+		t.Receive = 0
+	}
+}
+
+// ms converts the seconds to milliseconds
+func ms(t proto.MonotonicTime) float64 {
+	return float64(t.Duration()) / float64(time.Millisecond)
+}
+
+func harHeaders(headers proto.NetworkHeaders) []*HARNameValue {
+	list := []*HARNameValue{}
+	for k, v := range headers {
+		// multiple values of the same header are separated by "\n"
+		for _, val := range strings.Split(v.String(), "\n") {
+			list = append(list, &HARNameValue{Name: k, Value: val})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+func harHeader(headers proto.NetworkHeaders, name string) string {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v.String()
+		}
+	}
+	return ""
+}
+
+func harQuery(u string) []*HARNameValue {
+	list := []*HARNameValue{}
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return list
+	}
+	for k, vs := range parsed.Query() {
+		for _, v := range vs {
+			list = append(list, &HARNameValue{Name: k, Value: v})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+func harHTTPVersion(protocol string) string {
+	switch protocol {
+	case "h2":
+		return "HTTP/2.0"
+	case "h3", "h3-29":
+		return "HTTP/3.0"
+	case "http/1.0":
+		return "HTTP/1.0"
+	default:
+		return "HTTP/1.1"
+	}
+}
+
+// ReplayHAR fulfills the requests with the entries from the HAR file.
+// The entries are matched via the method, url, and post data of the request, if the same request is recorded
+// multiple times, they will be replayed in order and the last one will be repeated.
+// The requests that don't match any entry will fail with proto.NetworkErrorReasonInternetDisconnected,
+// so the page can work without network. Add your own handlers before it to handle them.
+func (r *HijackRouter) ReplayHAR(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	har, err := ReadHAR(f)
+	if err != nil {
+		return err
+	}
+
+	return r.Add("*", "", newHARReplayer(har).handle)
+}
+
+type harReplayer struct {
+	lock    *sync.Mutex
+	entries map[string][]*HAREntry
+}
+
+func newHARReplayer(har *HAR) *harReplayer {
+	entries := map[string][]*HAREntry{}
+	for _, e := range har.Log.Entries {
+		if e.Request == nil || e.Response == nil || e.Error != "" {
+			continue
+		}
+		body := ""
+		if e.Request.PostData != nil {
+			body = e.Request.PostData.Text
+		}
+		key := harKey(e.Request.Method, e.Request.URL, body)
+		entries[key] = append(entries[key], e)
+	}
+
+	return &harReplayer{
+		lock:    &sync.Mutex{},
+		entries: entries,
+	}
+}
+
+func (rp *harReplayer) next(method, u, body string) *HAREntry {
+	rp.lock.Lock()
+	defer rp.lock.Unlock()
+
+	key := harKey(method, u, body)
+	list := rp.entries[key]
+	if len(list) == 0 {
+		return nil
+	}
+
+	e := list[0]
+	if len(list) > 1 {
+		rp.entries[key] = list[1:]
+	}
+	return e
+}
+
+func (rp *harReplayer) handle(ctx *Hijack) {
+	e := rp.next(ctx.Request.Method(), ctx.Request.URL().String(), ctx.Request.Body())
+	if e == nil {
+		ctx.Response.Fail(proto.NetworkErrorReasonInternetDisconnected)
+		return
+	}
+
+	res := ctx.Response
+	res.Payload().ResponseCode = e.Response.Status
+	res.Payload().ResponsePhrase = e.Response.StatusText
+	for _, h := range e.Response.Headers {
+		// the body is already decoded and the size of it may change
+		if !isBodyEncodingHeader(h.Name) {
+			res.SetHeader(h.Name, h.Value)
+		}
+	}
+	res.SetBody(e.Response.Content.Body())
+}
+
+func harKey(method, u, body string) string {
+	return strings.ToUpper(method) + " " + strings.SplitN(u, "#", 2)[0] + "\n" + body
+}
+
+// WriteHAR to a writer
+func WriteHAR(w io.Writer, har *HAR) error {
+	return json.NewEncoder(w).Encode(har)
+}
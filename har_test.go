@@ -0,0 +1,92 @@
+package rod_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/go-rod/rod/lib/utils"
+)
+
+func (t T) RecordHAR() {
+	s := t.Serve()
+	s.Route("/", ".html", `<html><body><script>
+		fetch('/a', { method: 'POST', body: 'test' })
+	</script></body></html>`)
+	s.Route("/a", ".json", `{"a":1}`)
+
+	p := t.newPage()
+
+	buf := bytes.NewBuffer(nil)
+	stop := p.MustRecordHAR(buf)
+	wait := p.MustWaitRequestIdle()
+	p.MustNavigate(s.URL("/?q=1"))
+	wait()
+	stop()
+
+	har, err := rod.ReadHAR(buf)
+	t.E(err)
+	t.Eq("1.2", har.Log.Version)
+
+	var doc, api *rod.HAREntry
+	for _, e := range har.Log.Entries {
+		switch e.Request.URL {
+		case s.URL("/?q=1"):
+			doc = e
+		case s.URL("/a"):
+			api = e
+		}
+	}
+
+	t.Eq(proto.NetworkResourceTypeDocument, doc.ResourceType)
+	t.Eq("GET", doc.Request.Method)
+	t.Eq("q", doc.Request.QueryString[0].Name)
+	t.Eq(200, doc.Response.Status)
+	t.Has(string(doc.Response.Content.Body()), "fetch('/a'")
+	t.Gt(doc.Time, 0)
+
+	t.Eq("POST", api.Request.Method)
+	t.Eq("test", api.Request.PostData.Text)
+	t.Eq(`{"a":1}`, string(api.Response.Content.Body()))
+}
+
+func (t T) ReplayHAR() {
+	s := t.Serve()
+	s.Route("/", ".html", `<html><body><script>
+		const post = async (body) => (await fetch('/b', { method: 'POST', body })).text()
+		fetch('/a').then(r => r.text()).then(async t => document.title = t + await post('x') + await post('y'))
+	</script></body></html>`)
+	s.Route("/a", ".txt", `ok`)
+	s.Mux.HandleFunc("/b", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(w, r.Body)
+	})
+
+	p := t.newPage()
+
+	buf := bytes.NewBuffer(nil)
+	stop := p.MustRecordHAR(buf)
+	p.MustNavigate(s.URL()).MustWait(`document.title === 'okxy'`)
+	stop()
+
+	// make the recorded urls point to a host that doesn't exist
+	har := strings.ReplaceAll(buf.String(), s.URL(), "http://not-exists.test")
+	file := filepath.Join("tmp", "har", t.Srand(8)+".har")
+	t.E(utils.OutputFile(file, har))
+
+	page := t.newPage()
+	router := page.HijackRequests()
+	defer router.MustStop()
+
+	t.Err(router.ReplayHAR("not-exists.har"))
+	router.MustReplayHAR(file)
+	go router.Run()
+
+	page.MustNavigate("http://not-exists.test").MustWait(`document.title === 'okxy'`)
+
+	// the posts to the same url are matched via their bodies
+	t.Eq("x", page.MustEval(`() => post('x')`).Str())
+}
@@ -3,11 +3,16 @@ package rod
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/go-rod/rod/lib/utils"
@@ -33,6 +38,7 @@ func (p *Page) HijackRequests() *HijackRouter {
 type HijackRouter struct {
 	run      func()
 	stop     func()
+	lock     *sync.Mutex
 	handlers []*hijackHandler
 	enable   *proto.FetchEnable
 	client   proto.Client
@@ -44,6 +50,7 @@ func newHijackRouter(browser *Browser, client proto.Client) *HijackRouter {
 		enable:   &proto.FetchEnable{},
 		browser:  browser,
 		client:   client,
+		lock:     &sync.Mutex{},
 		handlers: []*hijackHandler{},
 	}
 }
@@ -62,18 +69,23 @@ func (r *HijackRouter) initEvents() *HijackRouter {
 	eventCtx, cancel := context.WithCancel(ctx)
 	r.stop = cancel
 
-	_ = r.enable.Call(r.client)
+	_ = r.update()
 
 	r.run = r.browser.Context(eventCtx).eachEvent(proto.TargetSessionID(sessionID), func(e *proto.FetchRequestPaused) bool {
 		go func() {
 			ctx := r.new(eventCtx, e)
-			for _, h := range r.handlers {
-				if !h.regexp.MatchString(e.Request.URL) {
+			defer ctx.Response.closeBodyStream()
+
+			for _, h := range r.getHandlers() {
+				if !h.match(ctx.Request) || !r.reserve(h) {
 					continue
 				}
 
 				h.handler(ctx)
 
+				// a skipped request doesn't count as a hit of the handler
+				r.hit(h, !ctx.Skip || ctx.continueRequest != nil)
+
 				if ctx.continueRequest != nil {
 					ctx.continueRequest.RequestID = e.RequestID
 					err := ctx.continueRequest.Call(r.client)
@@ -84,6 +96,7 @@ func (r *HijackRouter) initEvents() *HijackRouter {
 				}
 
 				if ctx.Skip {
+					ctx.Skip = false
 					continue
 				}
 
@@ -95,11 +108,17 @@ func (r *HijackRouter) initEvents() *HijackRouter {
 					return
 				}
 
-				err := ctx.Response.payload.Call(r.client)
+				err := ctx.Response.fulfill()
 				if err != nil {
 					ctx.OnError(err)
-					return
 				}
+				return
+			}
+
+			// no handler takes the request, let it go as is
+			err := proto.FetchContinueRequest{RequestID: e.RequestID}.Call(r.client)
+			if err != nil {
+				ctx.OnError(err)
 			}
 		}()
 
@@ -108,39 +127,188 @@ func (r *HijackRouter) initEvents() *HijackRouter {
 	return r
 }
 
+// HijackRoute is the rule to match the requests for a hijack handler.
+// A request must match all the non-empty fields to be handled.
+type HijackRoute struct {
+	// Pattern of the url, the doc of it is the same as "proto.FetchRequestPattern.URLPattern".
+	// If it's empty, "*" will be used.
+	Pattern string
+
+	// ResourceType of the request
+	ResourceType proto.NetworkResourceType
+
+	// Stage to handle the request, the default is proto.FetchRequestStageRequest.
+	// Check HijackRouter.AddResponse for the response stage.
+	Stage proto.FetchRequestStage
+
+	// Method of the request, such as "POST", case insensitive
+	Method string
+
+	// URL regexp to test the full url of the request
+	URL *regexp.Regexp
+
+	// Headers of the request, the value of each key is a regexp to test the header value
+	Headers map[string]*regexp.Regexp
+
+	// Filter the request with custom logic, such as to check the HijackRequest.JSONBody
+	Filter func(*HijackRequest) bool
+
+	// Priority of the route, the route with higher priority will be tried first.
+	// The routes with the same priority will be tried by the order they are added.
+	Priority int
+
+	// Times the route can handle, the route will be removed after it handles the requests Times times.
+	// The requests that the handler skips via the Hijack.Skip are not counted. Zero means no limit.
+	Times int
+}
+
 // Add a hijack handler to router, the doc of the pattern is the same as "proto.FetchRequestPattern.URLPattern".
 // You can add new handler even after the "Run" is called.
 func (r *HijackRouter) Add(pattern string, resourceType proto.NetworkResourceType, handler func(*Hijack)) error {
-	r.enable.Patterns = append(r.enable.Patterns, &proto.FetchRequestPattern{
-		URLPattern:   pattern,
+	return r.AddRoute(&HijackRoute{
+		Pattern:      pattern,
 		ResourceType: resourceType,
-	})
+	}, handler)
+}
+
+// AddResponse is similar to Add, but the handler will be called after the browser receives the response headers,
+// before the response is passed to the page. The Hijack.Response will be prefilled with the status code, headers,
+// and body the browser received, so that you can patch them without sending the request again,
+// the cookies, TLS, and HTTP/2 of the browser will be kept. Use Hijack.ContinueRequest with an empty
+// proto.FetchContinueRequest to pass the response to the page as it is.
+func (r *HijackRouter) AddResponse(pattern string, resourceType proto.NetworkResourceType, handler func(*Hijack)) error {
+	return r.AddRoute(&HijackRoute{
+		Pattern:      pattern,
+		ResourceType: resourceType,
+		Stage:        proto.FetchRequestStageResponse,
+	}, handler)
+}
+
+// AddRoute is similar to Add, but uses the route to match the requests.
+// Use HijackRouter.RemoveRoute to remove it.
+func (r *HijackRouter) AddRoute(route *HijackRoute, handler func(*Hijack)) error {
+	pattern := route.Pattern
+	if pattern == "" {
+		pattern = "*"
+	}
 
-	reg := regexp.MustCompile(proto.PatternToReg(pattern))
+	stage := route.Stage
+	if stage == "" {
+		stage = proto.FetchRequestStageRequest
+	}
 
-	r.handlers = append(r.handlers, &hijackHandler{
-		pattern: pattern,
-		regexp:  reg,
+	h := &hijackHandler{
+		route: route,
+		pattern: &proto.FetchRequestPattern{
+			URLPattern:   pattern,
+			ResourceType: route.ResourceType,
+			RequestStage: stage,
+		},
+		regexp:  regexp.MustCompile(proto.PatternToReg(pattern)),
 		handler: handler,
+	}
+
+	r.lock.Lock()
+	i := sort.Search(len(r.handlers), func(i int) bool {
+		return r.handlers[i].route.Priority < route.Priority
 	})
+	r.handlers = append(r.handlers, nil)
+	copy(r.handlers[i+1:], r.handlers[i:])
+	r.handlers[i] = h
+	r.lock.Unlock()
 
-	return r.enable.Call(r.client)
+	return r.update()
 }
 
-// Remove handler via the pattern
+// Remove handler via the pattern, the empty pattern is the same as "*", such as the routes that only
+// have the Method or URL.
 func (r *HijackRouter) Remove(pattern string) error {
-	patterns := []*proto.FetchRequestPattern{}
+	if pattern == "" {
+		pattern = "*"
+	}
+	return r.remove(func(h *hijackHandler) bool {
+		return h.pattern.URLPattern == pattern
+	})
+}
+
+// RemoveRoute removes the handler of the route
+func (r *HijackRouter) RemoveRoute(route *HijackRoute) error {
+	return r.remove(func(h *hijackHandler) bool {
+		return h.route == route
+	})
+}
+
+func (r *HijackRouter) remove(match func(*hijackHandler) bool) error {
+	r.lock.Lock()
 	handlers := []*hijackHandler{}
 	for _, h := range r.handlers {
-		if h.pattern != pattern {
-			patterns = append(patterns, &proto.FetchRequestPattern{URLPattern: h.pattern})
+		if !match(h) {
 			handlers = append(handlers, h)
 		}
 	}
-	r.enable.Patterns = patterns
 	r.handlers = handlers
+	r.lock.Unlock()
+
+	return r.update()
+}
+
+// update the fetch patterns to the browser
+func (r *HijackRouter) update() error {
+	r.lock.Lock()
+	patterns := []*proto.FetchRequestPattern{}
+	for _, h := range r.handlers {
+		patterns = append(patterns, h.pattern)
+	}
+	r.enable.Patterns = patterns
+	enable := *r.enable
+	r.lock.Unlock()
+
+	return enable.Call(r.client)
+}
 
-	return r.enable.Call(r.client)
+func (r *HijackRouter) getHandlers() []*hijackHandler {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]*hijackHandler{}, r.handlers...)
+}
+
+// reserve a handling of the handler, returns false if the handler is used up.
+// The reservation must be released by the hit after the handler is called.
+func (r *HijackRouter) reserve(h *hijackHandler) bool {
+	if h.route.Times == 0 {
+		return true
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if h.hits+h.pending >= h.route.Times {
+		return false
+	}
+	h.pending++
+	return true
+}
+
+// hit releases the reservation, if the handler handled the request it counts the handling times of the handler,
+// the handler will be removed after it's used up.
+func (r *HijackRouter) hit(h *hijackHandler, handled bool) {
+	if h.route.Times == 0 {
+		return
+	}
+
+	r.lock.Lock()
+	h.pending--
+	if handled {
+		h.hits++
+	}
+	done := h.hits >= h.route.Times
+	r.lock.Unlock()
+
+	if handled && done {
+		_ = r.remove(func(handler *hijackHandler) bool {
+			return handler == h
+		})
+	}
 }
 
 // new context
@@ -159,16 +327,31 @@ func (r *HijackRouter) new(ctx context.Context, e *proto.FetchRequestPaused) *Hi
 		Header: headers,
 	}
 
+	payload := &proto.FetchFulfillRequest{
+		ResponseCode: 200,
+		RequestID:    e.RequestID,
+	}
+
+	// prefill the response that the browser received
+	if e.ResponseStatusCode != 0 {
+		payload.ResponseCode = e.ResponseStatusCode
+		for _, h := range e.ResponseHeaders {
+			// the body will be decoded, the size of it may change
+			if !isBodyEncodingHeader(h.Name) {
+				payload.ResponseHeaders = append(payload.ResponseHeaders, h)
+			}
+		}
+	}
+
 	return &Hijack{
 		Request: &HijackRequest{
 			event: e,
 			req:   req.WithContext(ctx),
 		},
 		Response: &HijackResponse{
-			payload: &proto.FetchFulfillRequest{
-				ResponseCode: 200,
-				RequestID:    e.RequestID,
-			},
+			client:  r.client,
+			event:   e,
+			payload: payload,
 			fail: &proto.FetchFailRequest{
 				RequestID: e.RequestID,
 			},
@@ -177,6 +360,14 @@ func (r *HijackRouter) new(ctx context.Context, e *proto.FetchRequestPaused) *Hi
 	}
 }
 
+func isBodyEncodingHeader(name string) bool {
+	switch strings.ToLower(name) {
+	case "content-encoding", "content-length":
+		return true
+	}
+	return false
+}
+
 // Run the router, after you call it, you shouldn't add new handler to it.
 func (r *HijackRouter) Run() {
 	r.run()
@@ -188,11 +379,43 @@ func (r *HijackRouter) Stop() error {
 	return proto.FetchDisable{}.Call(r.client)
 }
 
-// hijackHandler to handle each request that match the regexp
+// hijackHandler to handle each request that match the route
 type hijackHandler struct {
-	pattern string
+	route   *HijackRoute
+	pattern *proto.FetchRequestPattern
 	regexp  *regexp.Regexp
 	handler func(*Hijack)
+	hits    int
+	pending int // the reserved handlings that are still running
+}
+
+func (h *hijackHandler) match(req *HijackRequest) bool {
+	e := req.event
+	route := h.route
+
+	if req.Stage() != h.pattern.RequestStage || !h.regexp.MatchString(e.Request.URL) {
+		return false
+	}
+
+	if route.ResourceType != "" && route.ResourceType != e.ResourceType {
+		return false
+	}
+
+	if route.Method != "" && !strings.EqualFold(route.Method, e.Request.Method) {
+		return false
+	}
+
+	if route.URL != nil && !route.URL.MatchString(e.Request.URL) {
+		return false
+	}
+
+	for k, reg := range route.Headers {
+		if !reg.MatchString(req.headerValue(k)) {
+			return false
+		}
+	}
+
+	return route.Filter == nil || route.Filter(req)
 }
 
 // Hijack context
@@ -211,6 +434,7 @@ type Hijack struct {
 }
 
 // ContinueRequest without hijacking. The RequestID will be set by the router, you don't have to set it.
+// For the handlers added by HijackRouter.AddResponse, the request can't be modified, cq should be empty.
 func (h *Hijack) ContinueRequest(cq *proto.FetchContinueRequest) {
 	h.continueRequest = cq
 }
@@ -224,27 +448,45 @@ func (h *Hijack) LoadResponse(client *http.Client, loadBody bool) error {
 
 	defer func() { _ = res.Body.Close() }()
 
-	h.Response.payload.ResponseCode = int(res.StatusCode)
-
-	list := []string{}
-	for k, vs := range res.Header {
-		for _, v := range vs {
-			list = append(list, k, v)
-		}
-	}
-	h.Response.SetHeader(list...)
+	h.loadResponseHeader(res)
 
 	if loadBody {
 		b, err := ioutil.ReadAll(res.Body)
 		if err != nil {
 			return err
 		}
-		h.Response.payload.Body = b
+		h.Response.SetBody(b)
+	}
+
+	return nil
+}
+
+// LoadResponseStream is similar to LoadResponse, but the body won't be read into memory in advance,
+// it will be passed to the browser via HijackResponse.SetBodyStream . Check SetBodyStream for the memory usage.
+func (h *Hijack) LoadResponseStream(client *http.Client) error {
+	res, err := client.Do(h.Request.req)
+	if err != nil {
+		return err
 	}
 
+	h.loadResponseHeader(res)
+	h.Response.SetBodyStream(res.Body)
+
 	return nil
 }
 
+func (h *Hijack) loadResponseHeader(res *http.Response) {
+	h.Response.payload.ResponseCode = int(res.StatusCode)
+
+	list := []string{}
+	for k, vs := range res.Header {
+		for _, v := range vs {
+			list = append(list, k, v)
+		}
+	}
+	h.Response.SetHeader(list...)
+}
+
 // HijackRequest context
 type HijackRequest struct {
 	event *proto.FetchRequestPaused
@@ -272,6 +514,16 @@ func (ctx *HijackRequest) Header(key string) string {
 	return ctx.event.Request.Headers[key].String()
 }
 
+// get the header value case-insensitively
+func (ctx *HijackRequest) headerValue(key string) string {
+	for k, v := range ctx.event.Request.Headers {
+		if strings.EqualFold(k, key) {
+			return v.String()
+		}
+	}
+	return ""
+}
+
 // Headers of request
 func (ctx *HijackRequest) Headers() proto.NetworkHeaders {
 	return ctx.event.Request.Headers
@@ -316,6 +568,14 @@ func (ctx *HijackRequest) SetBody(obj interface{}) *HijackRequest {
 	return ctx
 }
 
+// Stage of the request, it's proto.FetchRequestStageResponse when the response is received.
+func (ctx *HijackRequest) Stage() proto.FetchRequestStage {
+	if ctx.event.ResponseStatusCode != 0 || ctx.event.ResponseErrorReason != "" {
+		return proto.FetchRequestStageResponse
+	}
+	return proto.FetchRequestStageRequest
+}
+
 // IsNavigation determines whether the request is a navigation request
 func (ctx *HijackRequest) IsNavigation() bool {
 	return ctx.Type() == proto.NetworkResourceTypeDocument
@@ -323,17 +583,34 @@ func (ctx *HijackRequest) IsNavigation() bool {
 
 // HijackResponse context
 type HijackResponse struct {
+	client  proto.Client
+	event   *proto.FetchRequestPaused
 	payload *proto.FetchFulfillRequest
 	fail    *proto.FetchFailRequest
+
+	loaded     bool // if the body the browser received is loaded or replaced
+	bodyStream io.Reader
+	bodyErr    error
 }
 
 // Payload to respond the request from the browser.
+// For the handlers added by HijackRouter.AddResponse, the Body of it is empty until HijackResponse.Body is called.
 func (ctx *HijackResponse) Payload() *proto.FetchFulfillRequest {
 	return ctx.payload
 }
 
-// Body of the payload
+// Body of the payload. For the handlers added by HijackRouter.AddResponse, the body the browser received
+// will be loaded the first time it's called. If the body is set via SetBodyStream, it will be read into memory.
 func (ctx *HijackResponse) Body() string {
+	ctx.loadBody()
+
+	if ctx.bodyStream != nil {
+		b, err := ioutil.ReadAll(ctx.bodyStream)
+		ctx.closeBodyStream()
+		ctx.payload.Body = b
+		ctx.bodyErr = err
+	}
+
 	return string(ctx.payload.Body)
 }
 
@@ -361,6 +638,9 @@ func (ctx *HijackResponse) SetHeader(pairs ...string) *HijackResponse {
 
 // SetBody of the payload, if obj is []byte or string, raw body will be used, else it will be encoded as json.
 func (ctx *HijackResponse) SetBody(obj interface{}) *HijackResponse {
+	ctx.loaded = true
+	ctx.closeBodyStream()
+
 	switch body := obj.(type) {
 	case []byte:
 		ctx.payload.Body = body
@@ -372,12 +652,130 @@ func (ctx *HijackResponse) SetBody(obj interface{}) *HijackResponse {
 	return ctx
 }
 
+// ErrorReason of the response the browser received, it's empty if the response is received successfully.
+// Only available for the handlers added by HijackRouter.AddResponse .
+func (ctx *HijackResponse) ErrorReason() proto.NetworkErrorReason {
+	return ctx.event.ResponseErrorReason
+}
+
+// BodyStream of the response the browser received, it reads the body chunk by chunk via IO.read instead of
+// loading the whole body into memory. Only available for the handlers added by HijackRouter.AddResponse,
+// and it should be called before HijackResponse.Body . Once the stream is taken, the body of the payload will be empty,
+// use SetBody or SetBodyStream to provide the body for the browser. Only the reading side is truly streamed,
+// writing the body back via SetBodyStream is still fully buffered. For example, to save a large file
+// while passing it to the browser:
+//
+//     stream, _ := ctx.Response.BodyStream()
+//     ctx.Response.SetBodyStream(io.TeeReader(stream, file))
+//
+func (ctx *HijackResponse) BodyStream() (*StreamReader, error) {
+	res, err := proto.FetchTakeResponseBodyAsStream{RequestID: ctx.event.RequestID}.Call(ctx.client)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.loaded = true
+	ctx.payload.Body = nil
+
+	return NewStreamReader(ctx.client, res.Stream), nil
+}
+
+// SetBodyStream of the payload. The r will be read when the router responds the browser,
+// if r is an io.Closer, it will be closed after that.
+// Writing a body is still fully buffered, because Fetch.fulfillRequest requires the whole body in a single message,
+// the r will be drained into one buffer as base64 before the sending. Only the response stage pass-through
+// via HijackResponse.BodyStream (Fetch.takeResponseBodyAsStream) is truly streamed.
+func (ctx *HijackResponse) SetBodyStream(r io.Reader) *HijackResponse {
+	ctx.closeBodyStream()
+	ctx.loaded = true
+	ctx.payload.Body = nil
+	ctx.bodyStream = r
+	return ctx
+}
+
+// load the response body the browser received
+func (ctx *HijackResponse) loadBody() {
+	if ctx.loaded || ctx.event.ResponseStatusCode == 0 {
+		return
+	}
+	ctx.loaded = true
+
+	// such as redirect response doesn't have body, we just ignore the error
+	res, err := proto.FetchGetResponseBody{RequestID: ctx.event.RequestID}.Call(ctx.client)
+	if err != nil {
+		return
+	}
+
+	if res.Base64Encoded {
+		ctx.payload.Body, _ = base64.StdEncoding.DecodeString(res.Body)
+	} else {
+		ctx.payload.Body = []byte(res.Body)
+	}
+}
+
+func (ctx *HijackResponse) closeBodyStream() {
+	if c, ok := ctx.bodyStream.(io.Closer); ok {
+		_ = c.Close()
+	}
+	ctx.bodyStream = nil
+}
+
+// fulfill the request with the payload
+func (ctx *HijackResponse) fulfill() error {
+	if ctx.bodyErr != nil {
+		return ctx.bodyErr
+	}
+
+	ctx.loadBody()
+
+	if ctx.bodyStream == nil {
+		return ctx.payload.Call(ctx.client)
+	}
+
+	// encode the params directly into a single buffer, the cdp client sends json.RawMessage as it is,
+	// so the body is only copied once while the stream is base64 encoded.
+	payload := *ctx.payload
+	payload.Body = nil
+	head, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	params := bytes.NewBuffer(head[:len(head)-1])
+	_, _ = params.WriteString(`,"body":"`)
+	enc := base64.NewEncoder(base64.StdEncoding, params)
+	_, err = io.Copy(enc, ctx.bodyStream)
+	if err != nil {
+		return err
+	}
+	_ = enc.Close()
+	_, _ = params.WriteString(`"}`)
+
+	return callClient(ctx.client, ctx.payload.ProtoReq(), json.RawMessage(params.Bytes()))
+}
+
 // Fail request
 func (ctx *HijackResponse) Fail(reason proto.NetworkErrorReason) *HijackResponse {
 	ctx.fail.ErrorReason = reason
 	return ctx
 }
 
+// call the method with the context and session of the client
+func callClient(c proto.Client, method string, params interface{}) error {
+	ctx := context.Background()
+	if cta, ok := c.(proto.Contextable); ok {
+		ctx = cta.GetContext()
+	}
+
+	sessionID := ""
+	if tsa, ok := c.(proto.Sessionable); ok {
+		sessionID = string(tsa.GetSessionID())
+	}
+
+	_, err := c.Call(ctx, sessionID, method, params)
+	return err
+}
+
 // HandleAuth for the next basic HTTP authentication.
 // It will prevent the popup that requires user to input user name and password.
 // Ref: https://developer.mozilla.org/en-US/docs/Web/HTTP/Authentication
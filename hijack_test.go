@@ -1,10 +1,15 @@
 package rod_test
 
 import (
+	"bytes"
 	"errors"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"regexp"
+	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/proto"
@@ -269,6 +274,153 @@ func (t T) HijackResponseErr() {
 	wg.Wait()
 }
 
+func (t T) HijackResponseStage() {
+	s := t.Serve()
+	s.Mux.HandleFunc("/a", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "a=b")
+		t.HandleHTTP(".html", `<p>ok</p>`)(w, r)
+	})
+	s.Route("/b", ".html", `<p>b</p>`)
+
+	router := t.page.HijackRequests()
+	defer router.MustStop()
+
+	count := int32(0)
+	router.MustAddResponse(s.URL("/a"), func(ctx *rod.Hijack) {
+		atomic.AddInt32(&count, 1)
+
+		t.Eq(proto.FetchRequestStageResponse, ctx.Request.Stage())
+		t.Eq("", ctx.Response.ErrorReason())
+		t.Eq(200, ctx.Response.Payload().ResponseCode)
+		t.Eq("a=b", ctx.Response.Headers().Get("Set-Cookie"))
+		t.Eq("<p>ok</p>", ctx.Response.Body())
+
+		ctx.Response.SetBody(strings.Replace(ctx.Response.Body(), "ok", "patched", 1))
+	})
+
+	router.MustAddResponse(s.URL("/b"), func(ctx *rod.Hijack) {
+		ctx.ContinueRequest(&proto.FetchContinueRequest{})
+	})
+
+	go router.Run()
+
+	t.page.MustNavigate(s.URL("/a"))
+	t.Eq("patched", t.page.MustElement("p").MustText())
+	t.Eq(int32(1), atomic.LoadInt32(&count))
+
+	t.page.MustNavigate(s.URL("/b"))
+	t.Eq("b", t.page.MustElement("p").MustText())
+}
+
+func (t T) HijackBodyStream() {
+	s := t.Serve()
+	body := strings.Repeat("ok", 100*1024)
+	s.Route("/a", ".txt", body)
+	s.Route("/b", ".txt", body)
+
+	router := t.page.HijackRequests()
+	defer router.MustStop()
+
+	buf := bytes.NewBuffer(nil)
+	router.MustAddResponse(s.URL("/a"), func(ctx *rod.Hijack) {
+		stream, err := ctx.Response.BodyStream()
+		t.E(err)
+		ctx.Response.SetBodyStream(io.TeeReader(stream, buf))
+	})
+
+	router.MustAdd(s.URL("/b"), func(ctx *rod.Hijack) {
+		t.E(ctx.LoadResponseStream(http.DefaultClient))
+	})
+
+	go router.Run()
+
+	t.Eq(body, t.page.MustNavigate(s.URL("/a")).MustElement("body").MustText())
+	t.Eq(body, buf.String())
+
+	t.Eq(body, t.page.MustNavigate(s.URL("/b")).MustElement("body").MustText())
+}
+
+func (t T) HijackRoute() {
+	s := t.Serve()
+	s.Route("/", ".html", `<html><body><script>
+		async function req(method, body) {
+			const res = await fetch('/a', { method, body, headers: { 'X-Test': 'yes' } })
+			return res.text()
+		}
+	</script></body></html>`)
+	s.Route("/a", ".txt", "origin")
+
+	router := t.page.HijackRequests()
+	defer router.MustStop()
+
+	router.MustAddRoute(&rod.HijackRoute{
+		Pattern: s.URL("/a"),
+		Method:  http.MethodGet,
+	}, func(ctx *rod.Hijack) {
+		ctx.Response.SetBody("get")
+	})
+
+	router.MustAddRoute(&rod.HijackRoute{
+		Pattern: s.URL("/a"),
+		Method:  http.MethodPost,
+		Headers: map[string]*regexp.Regexp{"x-test": regexp.MustCompile(`^yes$`)},
+		Filter: func(req *rod.HijackRequest) bool {
+			return req.JSONBody().Get("id").Int() == 1
+		},
+	}, func(ctx *rod.Hijack) {
+		ctx.Response.SetBody("post 1")
+	})
+
+	once := &rod.HijackRoute{
+		Pattern:  s.URL("/a"),
+		URL:      regexp.MustCompile(`/a$`),
+		Priority: 1,
+		Times:    1,
+	}
+	router.MustAddRoute(once, func(ctx *rod.Hijack) {
+		ctx.Response.SetBody("once")
+	})
+
+	go router.Run()
+
+	t.page.MustNavigate(s.URL())
+
+	req := func(method, body string) string {
+		return t.page.MustEval(`(m, b) => req(m, b || undefined)`, method, body).Str()
+	}
+
+	t.Eq("once", req("GET", ""))
+	t.Eq("get", req("GET", ""))
+	t.Eq("post 1", req("POST", `{"id":1}`))
+	t.Eq("origin", req("POST", `{"id":2}`))
+
+	router.MustAddRoute(once, func(ctx *rod.Hijack) {
+		ctx.Response.SetBody("again")
+	})
+	router.MustRemoveRoute(once)
+	t.Eq("get", req("GET", ""))
+
+	// the skipped request doesn't count
+	router.MustAddRoute(&rod.HijackRoute{Method: http.MethodPut, Times: 1}, func(ctx *rod.Hijack) {
+		if ctx.Request.Body() == "skip" {
+			ctx.Skip = true
+			return
+		}
+		ctx.Response.SetBody("put")
+	})
+	t.Eq("origin", req("PUT", "skip"))
+	t.Eq("put", req("PUT", ""))
+	t.Eq("origin", req("PUT", ""))
+
+	// the route without the Pattern can be removed via "*"
+	router.MustAddRoute(&rod.HijackRoute{Method: http.MethodDelete}, func(ctx *rod.Hijack) {
+		ctx.Response.SetBody("delete")
+	})
+	t.Eq("delete", req("DELETE", ""))
+	router.MustRemove("*")
+	t.Eq("origin", req("DELETE", ""))
+}
+
 func (t T) HandleAuth() {
 	s := t.Serve()
 
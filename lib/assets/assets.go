@@ -97,6 +97,7 @@ const MonitorPage = `<html>
         border-bottom: 1px solid #1413158c;
         display: flex;
         flex-direction: row;
+        align-items: center;
       }
       .error {
         color: #ff3f3f;
@@ -106,7 +107,17 @@ const MonitorPage = `<html>
         padding: 10px;
         margin: 0;
       }
-      input {
+      .operator {
+        font-family: sans-serif;
+        color: #ffd76b;
+        background: #3e361f;
+        border-bottom: 1px solid #1413158c;
+        display: none;
+        padding: 10px;
+        margin: 0;
+      }
+      input,
+      button {
         background: transparent;
         color: white;
         border: none;
@@ -115,14 +126,25 @@ const MonitorPage = `<html>
         padding: 5px;
         margin: 5px;
       }
+      button {
+        cursor: pointer;
+      }
+      label {
+        font-size: 0.8em;
+        margin: 5px;
+        white-space: nowrap;
+      }
       .title {
         flex: 2;
       }
       .url {
         flex: 5;
       }
-      .rate {
-        flex: 1;
+      .screen {
+        outline: none;
+      }
+      .screen.control {
+        cursor: crosshair;
       }
     </style>
   </head>
@@ -135,56 +157,238 @@ const MonitorPage = `<html>
         readonly
       />
       <input type="text" class="url" title="url of the remote page" readonly />
-      <input
-        type="number"
-        class="rate"
-        value="0.5"
-        min="0"
-        step="0.1"
-        title="refresh rate (second)"
-      />
+      <label title="forward the mouse and keyboard input to the remote page">
+        <input type="checkbox" class="control" /> control
+      </label>
+    </div>
+    <div class="operator">
+      The script is waiting for an operator: <span class="message"></span>
+      <button class="hand-back">Hand back</button>
     </div>
     <pre class="error"></pre>
-    <img class="screen" />
+    <img class="screen" tabindex="0" />
   </body>
   <script>
     const id = location.pathname.split('/').slice(-1)[0]
     const elImg = document.querySelector('.screen')
     const elTitle = document.querySelector('.title')
     const elUrl = document.querySelector('.url')
-    const elRate = document.querySelector('.rate')
+    const elControl = document.querySelector('.control')
     const elErr = document.querySelector('.error')
+    const elOperator = document.querySelector('.operator')
+    const elMessage = document.querySelector('.operator .message')
+    const elHandBack = document.querySelector('.hand-back')
 
     document.title = ` + "`" + `Rod Monitor - ${id}` + "`" + `
 
+    let ws = null
+    let metadata = null
+
+    function showError(err) {
+      if (err) {
+        elErr.style.display = 'block'
+        elErr.textContent = err + ''
+      } else {
+        elErr.style.display = 'none'
+      }
+    }
+
+    function connect() {
+      ws = new WebSocket(` + "`" + `ws://${location.host}/ws/page/${id}` + "`" + `)
+      ws.onmessage = (e) => {
+        const msg = JSON.parse(e.data)
+        if (msg.type === 'frame') {
+          metadata = msg.metadata
+          elImg.src = 'data:image/jpeg;base64,' + msg.data
+          elImg.style.maxWidth = innerWidth + 'px'
+          showError()
+        } else if (msg.type === 'control') {
+          elControl.disabled = !msg.enabled
+          if (!msg.enabled) {
+            elControl.checked = false
+            elControl.parentElement.title =
+              'the control is disabled, enable it via the rod.MonitorOptions.Control'
+          }
+        } else if (msg.type === 'error') {
+          showError(msg.error)
+        }
+      }
+      ws.onclose = () => {
+        ws = null
+        showError('live view disconnected, reconnecting...')
+        setTimeout(connect, 1000)
+      }
+    }
+
+    function send(e) {
+      if (!ws || ws.readyState !== WebSocket.OPEN || !elControl.checked) return
+      ws.send(JSON.stringify(e))
+    }
+
+    // convert the position on the image to the css pixels of the remote page
+    function position(e) {
+      if (!metadata) return { x: 0, y: 0 }
+      return {
+        x: (e.offsetX / elImg.clientWidth) * metadata.deviceWidth,
+        y: (e.offsetY / elImg.clientHeight) * metadata.deviceHeight,
+      }
+    }
+
+    function controlled(e) {
+      if (!elControl.checked) return false
+      e.preventDefault()
+      return true
+    }
+
+    elControl.onchange = () => {
+      elImg.classList.toggle('control', elControl.checked)
+      if (elControl.checked) elImg.focus()
+    }
+
+    let lastMove = 0
+    elImg.addEventListener('mousemove', (e) => {
+      if (!controlled(e)) return
+      const now = Date.now()
+      if (now - lastMove < 30) return
+      lastMove = now
+      send({ type: 'mousemove', ...position(e) })
+    })
+    for (const type of ['mousedown', 'mouseup']) {
+      elImg.addEventListener(type, (e) => {
+        if (!controlled(e)) return
+        elImg.focus()
+        send({ type, button: e.button, clickCount: e.detail, ...position(e) })
+      })
+    }
+    elImg.addEventListener('wheel', (e) => {
+      if (!controlled(e)) return
+      send({ type: 'wheel', deltaX: e.deltaX, deltaY: e.deltaY })
+    })
+    elImg.addEventListener('contextmenu', controlled)
+    elImg.addEventListener('dragstart', controlled)
+    for (const type of ['keydown', 'keyup']) {
+      elImg.addEventListener(type, (e) => {
+        if (!controlled(e)) return
+        send({ type, key: e.key })
+      })
+    }
+    elImg.addEventListener('paste', (e) => {
+      if (!controlled(e)) return
+      send({ type: 'text', text: e.clipboardData.getData('text') })
+    })
+
+    elHandBack.onclick = async () => {
+      await fetch(` + "`" + `/api/operator/${id}` + "`" + `, { method: 'POST' })
+      elControl.checked = false
+      elControl.onchange()
+      elOperator.style.display = 'none'
+    }
+
     async function update() {
       const res = await fetch(` + "`" + `/api/page/${id}` + "`" + `)
       const info = await res.json()
       elTitle.value = info.title
       elUrl.value = info.url
 
-      await new Promise((resolve, reject) => {
-        const now = new Date()
-        elImg.src = ` + "`" + `/screenshot/${id}?t=${now.getTime()}` + "`" + `
-        elImg.style.maxWidth = innerWidth + 'px'
-        elImg.onload = resolve
-        elImg.onerror = () => reject(new Error('error loading screenshots'))
-      })
+      const op = await (await fetch(` + "`" + `/api/operator/${id}` + "`" + `)).json()
+      elOperator.style.display = op.waiting ? 'block' : 'none'
+      if (op.waiting) elMessage.textContent = op.request.message
     }
 
     async function mainLoop() {
       try {
         await update()
-        elErr.attributeStyleMap.delete('display')
       } catch (err) {
-        elErr.style.display = 'block'
-        elErr.textContent = err + ''
+        showError(err)
       }
 
-      setTimeout(mainLoop, parseFloat(elRate.value) * 1000)
+      setTimeout(mainLoop, 1000)
     }
 
+    // show a screenshot before the first frame, a static page may not send any frame
+    elImg.src = ` + "`" + `/screenshot/${id}` + "`" + `
+
+    connect()
     mainLoop()
   </script>
 </html>
 `
+
+// AuditReport for rod
+const AuditReport = `<html>
+  <head>
+    <title>Rod Accessibility Audit - {{.URL}}</title>
+    <style>
+      body {
+        margin: 0;
+        padding: 20px;
+        font-family: sans-serif;
+        color: #212225;
+      }
+      table {
+        border-collapse: collapse;
+        width: 100%;
+      }
+      th,
+      td {
+        text-align: left;
+        padding: 0.5em;
+        border-bottom: 1px solid #d0d0d6;
+        vertical-align: top;
+      }
+      code {
+        color: #8d2f9c;
+        word-break: break-all;
+      }
+      .summary span {
+        display: inline-block;
+        margin-right: 1em;
+        padding: 0.2em 0.5em;
+        border-radius: 0.3em;
+        background: #eeeef2;
+      }
+    </style>
+  </head>
+  <body>
+    <h3>Accessibility Audit</h3>
+    <p><a href="{{.URL}}">{{.URL}}</a> at {{.Time.Format "2006-01-02 15:04:05"}}</p>
+
+    <p class="summary">
+      {{range $rule, $count := .Summary}}<span>{{$rule}}: {{$count}}</span>{{end}}
+    </p>
+
+    <table>
+      <tr>
+        <th>Rule</th>
+        <th>WCAG</th>
+        <th>Message</th>
+        <th>Node</th>
+      </tr>
+      {{range .Findings}}
+      <tr>
+        <td>{{.Rule}}</td>
+        <td>{{.WCAG}}</td>
+        <td>{{.Message}}</td>
+        <td><code>{{.Node}}</code></td>
+      </tr>
+      {{end}}
+    </table>
+
+    {{if .Issues}}
+    <h3>Issues Reported by the Browser</h3>
+    <table>
+      <tr>
+        <th>Code</th>
+        <th>Details</th>
+      </tr>
+      {{range .Issues}}
+      <tr>
+        <td>{{.Code}}</td>
+        <td><code>{{json .Details}}</code></td>
+      </tr>
+      {{end}}
+    </table>
+    {{end}}
+  </body>
+</html>
+`
@@ -21,10 +21,14 @@ const Monitor = {{.monitor}}
 
 // MonitorPage for rod
 const MonitorPage = {{.monitorPage}}
+
+// AuditReport for rod
+const AuditReport = {{.auditReport}}
 `,
 		"mousePointer", get("../../fixtures/mouse-pointer.svg"),
 		"monitor", get("monitor.html"),
 		"monitorPage", get("monitor-page.html"),
+		"auditReport", get("audit-report.html"),
 	)
 
 	utils.E(utils.OutputFile(slash("lib/assets/assets.go"), build))
@@ -4,6 +4,7 @@ package cdp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"sync"
 	"sync/atomic"
@@ -20,6 +21,11 @@ type Client struct {
 	wsURL  string
 	header http.Header
 	ws     WebSocketable
+	wsLock sync.RWMutex
+
+	reconnect    func() utils.Sleeper
+	reconnectURL func(context.Context) (string, error)
+	noReconnect  int32 // set by StopReconnect
 
 	callbacks *sync.Map // buffer for response from browser
 
@@ -65,6 +71,10 @@ type WebSocketable interface {
 	Read() ([]byte, error)
 }
 
+// EventReconnected is the method of the event emitted by Client.Event after the client reconnects to the browser.
+// The sessions of the previous connection are no longer valid.
+const EventReconnected = "Client.reconnected"
+
 // New creates a cdp connection, all messages from Client.Event must be received or they will block the client.
 func New(websocketURL string) *Client {
 	return &Client{
@@ -89,6 +99,35 @@ func (cdp *Client) Websocket(ws WebSocketable) *Client {
 	return cdp
 }
 
+// Reconnect enables the client to re-dial the same websocket url when the connection drops,
+// the sleeper is used as the backoff between retries, the client will give up and close when the sleeper returns an error.
+// The calls that are waiting for their responses when the connection drops will fail with ErrConnClosed.
+// After reconnected an event with EventReconnected method will be emitted via Client.Event.
+// If the transport set via Client.Websocket is not the default WebSocket, it must support being connected again.
+// The rod.Browser.Close calls Client.StopReconnect, so that closing the browser won't be retried.
+func (cdp *Client) Reconnect(sleeper func() utils.Sleeper) *Client {
+	cdp.reconnect = sleeper
+	return cdp
+}
+
+// ReconnectURL sets the function to get the websocket url before each reconnection, such as the url of
+// a relaunched browser. If it returns an empty string the current url will be used,
+// if it returns error the client will give up and close. It only works with Reconnect.
+func (cdp *Client) ReconnectURL(fn func(context.Context) (string, error)) *Client {
+	cdp.reconnectURL = fn
+	return cdp
+}
+
+// StopReconnect disables the Reconnect, such as before closing the browser on purpose,
+// so that the drop of the connection closes the client immediately and fails the pending calls.
+func (cdp *Client) StopReconnect() {
+	atomic.StoreInt32(&cdp.noReconnect, 1)
+}
+
+func (cdp *Client) reconnectable() bool {
+	return cdp.reconnect != nil && atomic.LoadInt32(&cdp.noReconnect) == 0
+}
+
 // Logger sets the logger to log all the requests, responses, and events transferred between Rod and the browser.
 // The default format for each type is in file format.go
 func (cdp *Client) Logger(l utils.Logger) *Client {
@@ -125,7 +164,8 @@ func (cdp *Client) MustConnect(ctx context.Context) *Client {
 	return cdp
 }
 
-// Call a method and get its response, if ctx is nil context.Background() will be used
+// Call a method and get its response, if ctx is nil context.Background() will be used.
+// If the params is a json.RawMessage it will be sent as it is without being encoded again.
 func (cdp *Client) Call(ctx context.Context, sessionID, method string, params interface{}) ([]byte, error) {
 	req := &Request{
 		ID:        int(atomic.AddUint64(&cdp.count, 1)),
@@ -136,10 +176,10 @@ func (cdp *Client) Call(ctx context.Context, sessionID, method string, params in
 
 	cdp.logger.Println(req)
 
-	data, err := json.Marshal(req)
+	data, err := encodeRequest(req)
 	utils.E(err)
 
-	callback := make(chan *Response)
+	callback := make(chan *Response, 1)
 
 	cdp.callbacks.Store(req.ID, callback)
 	defer cdp.callbacks.Delete(req.ID)
@@ -162,6 +202,9 @@ func (cdp *Client) Call(ctx context.Context, sessionID, method string, params in
 		return nil, ctx.Err()
 
 	case res := <-callback:
+		if res == responseConnDropped {
+			return nil, &errConnClosed{errConnDropped}
+		}
 		if res.Error != nil {
 			return nil, res.Error
 		}
@@ -170,6 +213,25 @@ func (cdp *Client) Call(ctx context.Context, sessionID, method string, params in
 
 }
 
+// encode the request, the json.RawMessage params will be appended without validation and copy
+func encodeRequest(req *Request) ([]byte, error) {
+	raw, ok := req.Params.(json.RawMessage)
+	if !ok || len(raw) == 0 {
+		return json.Marshal(req)
+	}
+
+	head, err := json.Marshal(&Request{ID: req.ID, SessionID: req.SessionID, Method: req.Method})
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 0, len(head)+len(raw)+11)
+	data = append(data, head[:len(head)-1]...)
+	data = append(data, `,"params":`...)
+	data = append(data, raw...)
+	return append(data, '}'), nil
+}
+
 // Event returns a channel that will emit browser devtools protocol events. Must be consumed or will block producer.
 func (cdp *Client) Event() <-chan *Event {
 	return cdp.chEvent
@@ -183,8 +245,13 @@ func (cdp *Client) consumeMsg() {
 			return
 
 		case data := <-cdp.chReq:
-			err := cdp.ws.Send(data)
+			err := cdp.getWS().Send(data)
 			if err != nil {
+				if cdp.reconnectable() {
+					// the reader will reconnect, only fail the current call here
+					cdp.failCall(data)
+					continue
+				}
 				cdp.wsClose(err)
 				return
 			}
@@ -206,8 +273,11 @@ func (cdp *Client) readMsgFromBrowser() {
 	defer close(cdp.chEvent)
 
 	for {
-		data, err := cdp.ws.Read()
+		data, err := cdp.getWS().Read()
 		if err != nil {
+			if cdp.reconnectable() && cdp.reconnectWS(err) {
+				continue
+			}
 			cdp.wsClose(err)
 			return
 		}
@@ -246,3 +316,88 @@ func (cdp *Client) wsClose(err error) {
 	cdp.logger.Println(err)
 	cdp.close()
 }
+
+// it's a placeholder to notify the calls that the connection is dropped
+var responseConnDropped = &Response{}
+
+var errConnDropped = errors.New("connection dropped")
+
+func (cdp *Client) getWS() WebSocketable {
+	cdp.wsLock.RLock()
+	defer cdp.wsLock.RUnlock()
+	return cdp.ws
+}
+
+// reconnectWS returns true if it reconnects successfully
+func (cdp *Client) reconnectWS(err error) bool {
+	cdp.logger.Println(err)
+
+	// the responses of the pending calls will never come back
+	cdp.callbacks.Range(func(_, callback interface{}) bool {
+		select {
+		case callback.(chan *Response) <- responseConnDropped:
+		default:
+		}
+		return true
+	})
+
+	sleep := cdp.reconnect()
+	for {
+		if sleep(cdp.ctx) != nil || !cdp.reconnectable() {
+			return false
+		}
+
+		if cdp.reconnectURL != nil {
+			u, err := cdp.reconnectURL(cdp.ctx)
+			if err != nil {
+				cdp.logger.Println(err)
+				return false
+			}
+			if u != "" {
+				cdp.wsURL = u
+			}
+		}
+
+		ws := cdp.getWS()
+		if old, ok := ws.(*WebSocket); ok {
+			if old.close != nil {
+				old.close()
+			}
+			ws = &WebSocket{Dialer: old.Dialer}
+		}
+
+		err := ws.Connect(cdp.ctx, cdp.wsURL, cdp.header)
+		if err != nil {
+			cdp.logger.Println(err)
+			continue
+		}
+
+		cdp.wsLock.Lock()
+		cdp.ws = ws
+		cdp.wsLock.Unlock()
+
+		evt := &Event{Method: EventReconnected, Params: json.RawMessage("{}")}
+		cdp.logger.Println(evt)
+		select {
+		case <-cdp.ctx.Done():
+			return false
+		case cdp.chEvent <- evt:
+		}
+		return true
+	}
+}
+
+// failCall notifies the caller of the request that it's failed to send
+func (cdp *Client) failCall(data []byte) {
+	var req struct {
+		ID int `json:"id"`
+	}
+	_ = json.Unmarshal(data, &req)
+
+	if callback, has := cdp.callbacks.Load(req.ID); has {
+		select {
+		case callback.(chan *Response) <- responseConnDropped:
+		default:
+		}
+	}
+}
@@ -1,10 +1,15 @@
 package cdp
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/go-rod/rod/lib/utils"
 	"github.com/ysmood/got"
@@ -19,12 +24,16 @@ type T struct {
 }
 
 type MockWebSocket struct {
-	send func([]byte) error
-	read func() ([]byte, error)
+	connect func() error
+	send    func([]byte) error
+	read    func() ([]byte, error)
 }
 
 // Connect interface
 func (c *MockWebSocket) Connect(ctx context.Context, url string, header http.Header) error {
+	if c.connect != nil {
+		return c.connect()
+	}
 	return nil
 }
 
@@ -160,6 +169,219 @@ func (t T) CancelOnReadEvent() {
 	t.Err(err)
 }
 
+func (t T) Reconnect() {
+	ctx := t.Context()
+	cdp := New("").Reconnect(func() utils.Sleeper { return utils.CountSleeper(3) })
+	cdp.ctx = ctx
+	cdp.close = ctx.Cancel
+
+	down := int32(0)
+	allowConnect := make(chan struct{})
+	responses := make(chan []byte)
+
+	cdp.ws = &MockWebSocket{
+		connect: func() error {
+			<-allowConnect
+			atomic.StoreInt32(&down, 0)
+			return nil
+		},
+		send: func(b []byte) error {
+			if atomic.LoadInt32(&down) == 1 {
+				return errors.New("err")
+			}
+
+			var req Request
+			t.E(json.Unmarshal(b, &req))
+			go func() { responses <- utils.MustToJSONBytes(&Response{ID: req.ID}) }()
+			return nil
+		},
+		read: func() ([]byte, error) {
+			for {
+				if atomic.LoadInt32(&down) == 1 {
+					return nil, errors.New("err")
+				}
+				select {
+				case res := <-responses:
+					return res, nil
+				case <-time.After(10 * time.Millisecond):
+				}
+			}
+		},
+	}
+
+	go cdp.consumeMsg()
+	go cdp.readMsgFromBrowser()
+
+	_, err := cdp.Call(t.Context(), "", "", nil)
+	t.E(err)
+
+	atomic.StoreInt32(&down, 1)
+	_, err = cdp.Call(t.Context(), "", "", nil)
+	t.Is(err, ErrConnClosed)
+
+	close(allowConnect)
+
+	e := <-cdp.Event()
+	t.Eq(EventReconnected, e.Method)
+
+	_, err = cdp.Call(t.Context(), "", "", nil)
+	t.E(err)
+}
+
+func (t T) ReconnectGiveUp() {
+	ctx := t.Context()
+	cdp := New("").Reconnect(func() utils.Sleeper { return utils.CountSleeper(2) })
+	cdp.ctx = ctx
+	cdp.close = ctx.Cancel
+	cdp.ws = &MockWebSocket{
+		connect: func() error { return errors.New("err") },
+		read:    func() ([]byte, error) { return nil, errors.New("err") },
+	}
+
+	go cdp.readMsgFromBrowser()
+
+	<-ctx.Done()
+	_, ok := <-cdp.Event()
+	t.False(ok)
+}
+
+func (t T) StopReconnect() {
+	ctx := t.Context()
+	cdp := New("").Reconnect(func() utils.Sleeper {
+		return utils.BackoffSleeper(time.Hour, time.Hour, nil)
+	})
+	cdp.ctx = ctx
+	cdp.close = ctx.Cancel
+	cdp.ws = &MockWebSocket{
+		connect: func() error { return nil },
+		send:    func([]byte) error { return errors.New("err") },
+		read:    func() ([]byte, error) { return nil, errors.New("err") },
+	}
+
+	cdp.StopReconnect()
+
+	go cdp.consumeMsg()
+	go cdp.readMsgFromBrowser()
+
+	_, err := cdp.Call(t.Timeout(time.Second), "", "", nil)
+	t.Is(err, ErrConnClosed)
+
+	<-ctx.Done()
+	_, ok := <-cdp.Event()
+	t.False(ok)
+}
+
+func (t T) ReconnectURL() {
+	ctx := t.Context()
+	cdp := New("ws://a").Reconnect(func() utils.Sleeper { return utils.CountSleeper(3) }).
+		ReconnectURL(func(context.Context) (string, error) { return "ws://b", nil })
+	cdp.ctx = ctx
+	cdp.close = ctx.Cancel
+
+	count := int32(0)
+	cdp.ws = &MockWebSocket{
+		connect: func() error { return nil },
+		read: func() ([]byte, error) {
+			if atomic.AddInt32(&count, 1) == 1 {
+				return nil, errors.New("err")
+			}
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+
+	go cdp.readMsgFromBrowser()
+
+	e := <-cdp.Event()
+	t.Eq(EventReconnected, e.Method)
+	t.Eq("ws://b", cdp.wsURL)
+
+	// give up when the url can't be resolved
+	giveUpCtx := t.Context()
+	giveUp := New("ws://a").Reconnect(func() utils.Sleeper { return utils.CountSleeper(3) }).
+		ReconnectURL(func(context.Context) (string, error) { return "", errors.New("err") })
+	giveUp.ctx = giveUpCtx
+	giveUp.close = giveUpCtx.Cancel
+	giveUp.ws = &MockWebSocket{
+		read: func() ([]byte, error) { return nil, errors.New("err") },
+	}
+
+	go giveUp.readMsgFromBrowser()
+
+	<-giveUpCtx.Done()
+	_, ok := <-giveUp.Event()
+	t.False(ok)
+}
+
 func (t T) TestError() {
 	t.Is(&Error{Code: -123}, &Error{Code: -123})
 }
+
+func (t T) RecordAndReplay() {
+	buf := bytes.NewBuffer(nil)
+
+	{ // record
+		responses := make(chan []byte, 10)
+		mock := &MockWebSocket{
+			send: func(b []byte) error {
+				var req Request
+				t.E(json.Unmarshal(b, &req))
+				responses <- utils.MustToJSONBytes(&Event{Method: "Test.event"})
+				responses <- utils.MustToJSONBytes(&Response{ID: req.ID, Result: []byte(`"` + req.Method + `"`)})
+				return nil
+			},
+			read: func() ([]byte, error) { return <-responses, nil },
+		}
+
+		cdp := New("").Websocket(NewRecorder(mock, buf))
+		t.E(cdp.Connect(t.Context()))
+		go func() {
+			for range cdp.Event() {
+			}
+		}()
+
+		res, err := cdp.Call(t.Context(), "", "A.a", nil)
+		t.E(err)
+		t.Eq(`"A.a"`, string(res))
+		_, err = cdp.Call(t.Context(), "", "B.b", map[string]int{"x": 1})
+		t.E(err)
+	}
+
+	{ // replay
+		replayer, err := NewReplayer(buf)
+		t.E(err)
+
+		cdp := New("").Websocket(replayer)
+		cdp.count = 100 // the ids will be different from the recorded ones
+		t.E(cdp.Connect(t.Context()))
+
+		events := make(chan *Event, 10)
+		go func() {
+			for e := range cdp.Event() {
+				events <- e
+			}
+		}()
+
+		res, err := cdp.Call(t.Context(), "", "A.a", nil)
+		t.E(err)
+		t.Eq(`"A.a"`, string(res))
+		t.Eq("Test.event", (<-events).Method)
+
+		res, err = cdp.Call(t.Context(), "", "B.b", map[string]int{"x": 2})
+		t.E(err)
+		t.Eq(`"B.b"`, string(res))
+		t.Eq("Test.event", (<-events).Method)
+
+		_, err = cdp.Call(t.Context(), "", "C.c", nil)
+		t.Has(err.Error(), "no recorded request matches: C.c")
+	}
+
+	_, err := NewReplayer(strings.NewReader("{"))
+	t.Err(err)
+}
+
+func (t T) EncodeRawParams() {
+	data, err := encodeRequest(&Request{ID: 1, Method: "a", Params: json.RawMessage(`{"b":"c"}`)})
+	t.E(err)
+	t.Eq(string(data), `{"id":1,"method":"a","params":{"b":"c"}}`)
+}
@@ -58,6 +58,12 @@ var ErrNoContentQuads = &Error{
 	Message: "Could not compute content quads.",
 }
 
+// ErrTargetCrashed type
+var ErrTargetCrashed = &Error{
+	Code:    -32000,
+	Message: "Target crashed",
+}
+
 // ErrConnClosed type
 var ErrConnClosed = &errConnClosed{}
 
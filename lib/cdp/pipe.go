@@ -0,0 +1,64 @@
+package cdp
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+var _ WebSocketable = &Pipe{}
+
+// Pipe transport for the browser launched with the "--remote-debugging-pipe" flag.
+// The messages are NUL-delimited JSON, the browser reads the requests from fd 3 and writes
+// the responses and events to fd 4. Use it via Client.Websocket, the url of the Client will be ignored.
+type Pipe struct {
+	lock *sync.Mutex
+	w    io.WriteCloser
+	r    *bufio.Reader
+	rc   io.Closer
+}
+
+// NewPipe creates a pipe transport, w is the writer for fd 3 of the browser,
+// r is the reader for fd 4 of the browser.
+func NewPipe(w io.WriteCloser, r io.ReadCloser) *Pipe {
+	return &Pipe{
+		lock: &sync.Mutex{},
+		w:    w,
+		r:    bufio.NewReader(r),
+		rc:   r,
+	}
+}
+
+// Connect interface, the pipes will be closed when the ctx is done
+func (p *Pipe) Connect(ctx context.Context, _ string, _ http.Header) error {
+	go func() {
+		<-ctx.Done()
+		_ = p.w.Close()
+		_ = p.rc.Close()
+	}()
+	return nil
+}
+
+// Send a message to the browser
+func (p *Pipe) Send(msg []byte) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	_, err := p.w.Write(msg)
+	if err != nil {
+		return err
+	}
+	_, err = p.w.Write([]byte{0})
+	return err
+}
+
+// Read a message from the browser
+func (p *Pipe) Read() ([]byte, error) {
+	msg, err := p.r.ReadBytes(0)
+	if err != nil {
+		return nil, err
+	}
+	return msg[:len(msg)-1], nil
+}
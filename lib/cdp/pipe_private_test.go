@@ -0,0 +1,41 @@
+package cdp
+
+import (
+	"io"
+)
+
+func (t T) Pipe() {
+	// simulate the fd 3 and fd 4 of the browser
+	fd3r, fd3w := io.Pipe()
+	fd4r, fd4w := io.Pipe()
+
+	go func() {
+		buf := make([]byte, 1024)
+		n, _ := fd3r.Read(buf)
+		t.Eq(`{"id":1}`, string(buf[:n]))
+		n, _ = fd3r.Read(buf)
+		t.Eq("\x00", string(buf[:n]))
+
+		_, _ = fd4w.Write([]byte("a\x00b\x00"))
+		_ = fd4w.Close()
+	}()
+
+	ctx := t.Context()
+	p := NewPipe(fd3w, fd4r)
+	t.E(p.Connect(ctx, "", nil))
+	t.E(p.Send([]byte(`{"id":1}`)))
+
+	msg, err := p.Read()
+	t.E(err)
+	t.Eq("a", string(msg))
+
+	msg, err = p.Read()
+	t.E(err)
+	t.Eq("b", string(msg))
+
+	_, err = p.Read()
+	t.Eq(err, io.EOF)
+
+	ctx.Cancel()
+	t.E(fd4r.Close())
+}
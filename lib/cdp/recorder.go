@@ -0,0 +1,294 @@
+package cdp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/go-rod/rod/lib/utils"
+)
+
+// RecordedMessage is a line of the JSONL file written by the Recorder
+type RecordedMessage struct {
+	// Sent is true if the message is sent to the browser, false if it's received from the browser
+	Sent bool `json:"sent,omitempty"`
+
+	// Data of the message
+	Data json.RawMessage `json:"data"`
+}
+
+var _ WebSocketable = &Recorder{}
+
+// Recorder wraps a WebSocketable and writes the full conversation with the browser
+// to the writer as JSONL, each line is a RecordedMessage. Use the Replayer to serve it back.
+type Recorder struct {
+	ws   WebSocketable
+	lock *sync.Mutex
+	w    io.Writer
+}
+
+// NewRecorder wraps the ws, if ws is nil the default WebSocket will be used.
+func NewRecorder(ws WebSocketable, w io.Writer) *Recorder {
+	if ws == nil {
+		ws = &WebSocket{}
+	}
+	return &Recorder{ws: ws, lock: &sync.Mutex{}, w: w}
+}
+
+// Connect interface
+func (r *Recorder) Connect(ctx context.Context, url string, header http.Header) error {
+	return r.ws.Connect(ctx, url, header)
+}
+
+// Send interface
+func (r *Recorder) Send(msg []byte) error {
+	err := r.ws.Send(msg)
+	if err != nil {
+		return err
+	}
+	return r.write(true, msg)
+}
+
+// Read interface
+func (r *Recorder) Read() ([]byte, error) {
+	msg, err := r.ws.Read()
+	if err != nil {
+		return nil, err
+	}
+	return msg, r.write(false, msg)
+}
+
+func (r *Recorder) write(sent bool, msg []byte) error {
+	line, err := json.Marshal(&RecordedMessage{Sent: sent, Data: msg})
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	_, err = r.w.Write(append(line, '\n'))
+	return err
+}
+
+var _ WebSocketable = &Replayer{}
+
+// Replayer serves the conversation recorded by the Recorder back to the Client without a browser.
+// Each request sent by the client is matched with the first unused recorded request that has the same
+// method, session, and params. If the params are different, the first unused one with the same method
+// and session will be used. The ids of the responses will be rewritten to the ids of the requests.
+// The messages from the browser are read in the recorded order, a response will block the reading
+// until its request is sent. If no recorded request matches, an error response will be returned.
+type Replayer struct {
+	ctx  context.Context
+	lock *sync.Mutex
+	cond *sync.Cond
+
+	requests []*replayRequest
+	received []json.RawMessage
+	cursor   int
+
+	ids    map[int]int // recorded id to the id of the request sent by the client
+	errors []json.RawMessage
+}
+
+type replayRequest struct {
+	id        int
+	method    string
+	sessionID string
+	params    string
+	used      bool
+}
+
+type replayID struct {
+	ID        int             `json:"id"`
+	SessionID string          `json:"sessionId,omitempty"`
+	Method    string          `json:"method"`
+	Params    json.RawMessage `json:"params,omitempty"`
+}
+
+// NewReplayer loads the JSONL written by the Recorder
+func NewReplayer(r io.Reader) (*Replayer, error) {
+	rp := &Replayer{
+		ctx:  context.Background(),
+		lock: &sync.Mutex{},
+		ids:  map[int]int{},
+	}
+	rp.cond = sync.NewCond(rp.lock)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024*1024)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var msg RecordedMessage
+		err := json.Unmarshal(line, &msg)
+		if err != nil {
+			return nil, err
+		}
+
+		if msg.Sent {
+			var req replayID
+			err = json.Unmarshal(msg.Data, &req)
+			if err != nil {
+				return nil, err
+			}
+			rp.requests = append(rp.requests, &replayRequest{
+				id:        req.ID,
+				method:    req.Method,
+				sessionID: req.SessionID,
+				params:    compactJSON(req.Params),
+			})
+		} else {
+			rp.received = append(rp.received, msg.Data)
+		}
+	}
+
+	return rp, scanner.Err()
+}
+
+// Connect interface, the url is ignored. When the ctx is done the Read will return an error.
+func (rp *Replayer) Connect(ctx context.Context, _ string, _ http.Header) error {
+	rp.lock.Lock()
+	rp.ctx = ctx
+	rp.lock.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		rp.lock.Lock()
+		defer rp.lock.Unlock()
+		rp.cond.Broadcast()
+	}()
+
+	return nil
+}
+
+// Send interface
+func (rp *Replayer) Send(msg []byte) error {
+	var req replayID
+	err := json.Unmarshal(msg, &req)
+	if err != nil {
+		return err
+	}
+
+	rp.lock.Lock()
+	defer rp.lock.Unlock()
+	defer rp.cond.Broadcast()
+
+	if recorded := rp.match(&req); recorded != nil {
+		recorded.used = true
+		rp.ids[recorded.id] = req.ID
+		return nil
+	}
+
+	rp.errors = append(rp.errors, utils.MustToJSONBytes(&Response{
+		ID: req.ID,
+		Error: &Error{
+			Code:    -32000,
+			Message: fmt.Sprintf("no recorded request matches: %s", req.Method),
+		},
+	}))
+	return nil
+}
+
+// Read interface
+func (rp *Replayer) Read() ([]byte, error) {
+	rp.lock.Lock()
+	defer rp.lock.Unlock()
+
+	for {
+		if err := rp.ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if len(rp.errors) > 0 {
+			msg := rp.errors[0]
+			rp.errors = rp.errors[1:]
+			return msg, nil
+		}
+
+		if rp.cursor < len(rp.received) {
+			msg := rp.received[rp.cursor]
+
+			var res replayID
+			err := json.Unmarshal(msg, &res)
+			if err != nil {
+				return nil, err
+			}
+
+			if res.ID == 0 { // event
+				rp.cursor++
+				return msg, nil
+			}
+
+			if id, has := rp.ids[res.ID]; has {
+				rp.cursor++
+				return rewriteID(msg, id)
+			}
+
+			if !rp.expecting(res.ID) {
+				// the response of a request that will never be sent, such as the request isn't recorded
+				rp.cursor++
+				continue
+			}
+		}
+
+		rp.cond.Wait()
+	}
+}
+
+// match the first unused recorded request, must be called with the lock held
+func (rp *Replayer) match(req *replayID) *replayRequest {
+	params := compactJSON(req.Params)
+
+	var fallback *replayRequest
+	for _, r := range rp.requests {
+		if r.used || r.method != req.Method || r.sessionID != req.SessionID {
+			continue
+		}
+		if r.params == params {
+			return r
+		}
+		if fallback == nil {
+			fallback = r
+		}
+	}
+	return fallback
+}
+
+// expecting returns true if the recorded request of the id hasn't been sent
+func (rp *Replayer) expecting(id int) bool {
+	for _, r := range rp.requests {
+		if r.id == id {
+			return !r.used
+		}
+	}
+	return false
+}
+
+func rewriteID(msg json.RawMessage, id int) ([]byte, error) {
+	var res map[string]json.RawMessage
+	err := json.Unmarshal(msg, &res)
+	if err != nil {
+		return nil, err
+	}
+	res["id"] = utils.MustToJSONBytes(id)
+	return json.Marshal(res)
+}
+
+func compactJSON(data json.RawMessage) string {
+	buf := bytes.NewBuffer(nil)
+	if json.Compact(buf, data) != nil {
+		return string(data)
+	}
+	return buf.String()
+}
@@ -49,7 +49,12 @@ func (ws *WebSocket) Connect(ctx context.Context, wsURL string, header http.Head
 
 	ws.initDialer(u)
 
-	conn, err := ws.Dialer.DialContext(ctx, "tcp", u.Host)
+	addr := u.Host
+	if u.Scheme == "wss" && u.Port() == "" {
+		addr += ":443"
+	}
+
+	conn, err := ws.Dialer.DialContext(ctx, "tcp", addr)
 	if err != nil {
 		return err
 	}
@@ -73,9 +78,6 @@ func (ws *WebSocket) initDialer(u *url.URL) {
 
 	if u.Scheme == "wss" {
 		ws.Dialer = &tlsDialer{}
-		if u.Port() == "" {
-			u.Host += ":443"
-		}
 	} else {
 		ws.Dialer = &net.Dialer{}
 	}
@@ -39,6 +39,27 @@ func (t T) WebSocketErr() {
 	t.Err(tls.DialContext(context.Background(), "", ""))
 }
 
+func (t T) WebSocketDefaultPort() {
+	d := &addrDialer{}
+	ws := WebSocket{Dialer: d}
+	t.Err(ws.Connect(t.Context(), "wss://no-exist/a", nil))
+	t.Eq(d.addr, "no-exist:443")
+
+	ws = WebSocket{Dialer: d}
+	t.Err(ws.Connect(t.Context(), "wss://no-exist:8443/a", nil))
+	t.Eq(d.addr, "no-exist:8443")
+}
+
+// addrDialer records the address to dial
+type addrDialer struct {
+	addr string
+}
+
+func (d *addrDialer) DialContext(_ context.Context, _, addr string) (net.Conn, error) {
+	d.addr = addr
+	return nil, errors.New("err")
+}
+
 type MockConn struct {
 	errOnCount int
 	frame      []byte
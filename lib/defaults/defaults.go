@@ -45,6 +45,9 @@ var Proxy string
 // Lock is the default of launcher.Browser.Lock
 var Lock int
 
+// Mirror is the default host of launcher.Browser.Hosts, check launcher.HostMirror for the format
+var Mirror string
+
 // URL is the default of cdp.Client.New
 var URL string
 
@@ -63,6 +66,7 @@ func Reset() {
 	Bin = ""
 	Proxy = ""
 	Lock = 2978
+	Mirror = ""
 	URL = ""
 	CDP = utils.LoggerQuiet
 }
@@ -110,6 +114,9 @@ var rules = map[string]func(string){
 			Lock = int(i)
 		}
 	},
+	"mirror": func(v string) {
+		Mirror = v
+	},
 	"url": func(v string) {
 		URL = v
 	},
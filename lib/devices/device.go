@@ -12,6 +12,9 @@ type Device struct {
 	Screen         Screen
 	Title          string
 
+	// Network the device typically uses, nil means no network emulation
+	Network *Network
+
 	landscape bool
 	clear     bool
 }
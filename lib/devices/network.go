@@ -0,0 +1,99 @@
+package devices
+
+import (
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// Network represents an emulated network condition.
+type Network struct {
+	Title string
+
+	// Offline emulates the internet disconnection
+	Offline bool
+
+	// Latency from request sent to response headers received
+	Latency time.Duration
+
+	// DownloadThroughput in bytes per second, zero means no limit
+	DownloadThroughput float64
+
+	// UploadThroughput in bytes per second, zero means no limit
+	UploadThroughput float64
+
+	// PacketLoss percentage, such as 1.5 means 1.5% of the packets will be dropped.
+	// It requires a browser that supports the "packetLoss" parameter of Network.emulateNetworkConditions.
+	PacketLoss float64
+
+	ConnectionType proto.NetworkConnectionType
+}
+
+const kbps = 1024 / 8
+
+var (
+	// NetworkNoThrottle disables the network emulation
+	NetworkNoThrottle = Network{
+		Title: "No throttling",
+	}
+
+	// NetworkOffline network
+	NetworkOffline = Network{
+		Title:          "Offline",
+		Offline:        true,
+		ConnectionType: proto.NetworkConnectionTypeNone,
+	}
+
+	// NetworkSlow3G network
+	NetworkSlow3G = Network{
+		Title:              "Slow 3G",
+		Latency:            2 * time.Second,
+		DownloadThroughput: 500 * kbps * 0.8,
+		UploadThroughput:   500 * kbps * 0.8,
+		ConnectionType:     proto.NetworkConnectionTypeCellular3g,
+	}
+
+	// NetworkFast3G network
+	NetworkFast3G = Network{
+		Title:              "Fast 3G",
+		Latency:            562500 * time.Microsecond,
+		DownloadThroughput: 1.6 * 1024 * kbps * 0.9,
+		UploadThroughput:   750 * kbps * 0.9,
+		ConnectionType:     proto.NetworkConnectionTypeCellular3g,
+	}
+
+	// Network4G network
+	Network4G = Network{
+		Title:              "4G",
+		Latency:            20 * time.Millisecond,
+		DownloadThroughput: 4 * 1024 * kbps,
+		UploadThroughput:   3 * 1024 * kbps,
+		ConnectionType:     proto.NetworkConnectionTypeCellular4g,
+	}
+)
+
+// NetworkEmulation config
+func (n Network) NetworkEmulation() *proto.NetworkEmulateNetworkConditions {
+	return &proto.NetworkEmulateNetworkConditions{
+		Offline:            n.Offline,
+		Latency:            float64(n.Latency) / float64(time.Millisecond),
+		DownloadThroughput: throughput(n.DownloadThroughput),
+		UploadThroughput:   throughput(n.UploadThroughput),
+		ConnectionType:     n.ConnectionType,
+	}
+}
+
+// WithNetwork clones the device and set the network it typically uses,
+// Page.Emulate will emulate the network too.
+func (device Device) WithNetwork(n Network) Device {
+	d := device
+	d.Network = &n
+	return d
+}
+
+func throughput(v float64) float64 {
+	if v == 0 {
+		return -1
+	}
+	return v
+}
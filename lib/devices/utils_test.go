@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/go-rod/rod/lib/devices"
+	"github.com/go-rod/rod/lib/proto"
 	"github.com/ysmood/got"
 )
 
@@ -33,3 +34,23 @@ func TestErr(t *testing.T) {
 	as.False(devices.Clear.TouchEmulation().Enabled)
 	as.Nil(devices.Clear.UserAgentEmulation())
 }
+
+func TestNetwork(t *testing.T) {
+	as := got.New(t)
+
+	n := devices.NetworkSlow3G.NetworkEmulation()
+	as.Eq(2000, n.Latency)
+	as.Eq(51200, n.DownloadThroughput)
+	as.Eq(proto.NetworkConnectionTypeCellular3g, n.ConnectionType)
+
+	n = devices.NetworkNoThrottle.NetworkEmulation()
+	as.Eq(0, n.Latency)
+	as.Eq(-1, n.DownloadThroughput)
+	as.Eq(-1, n.UploadThroughput)
+
+	as.True(devices.NetworkOffline.NetworkEmulation().Offline)
+
+	d := devices.IPad.WithNetwork(devices.Network4G)
+	as.Eq("4G", d.Network.Title)
+	as.Nil(devices.IPad.Network)
+}
@@ -16,6 +16,9 @@ func main() {
 	// For more information, check the doc of launcher.RemoteLauncher
 	l := launcher.MustNewRemote("ws://localhost:9222")
 
+	// If the service is started with a token, such as "rod-launcher -token secret", use:
+	// launcher.MustNewRemote("ws://localhost:9222", &launcher.RemoteOptions{Token: "secret"})
+
 	// Manipulate flags like the example in examples_test.go
 	l.Set("any-flag").Delete("any-flag")
 
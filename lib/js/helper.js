@@ -52,6 +52,359 @@ const functions = {
     return el ? el : null
   },
 
+  elementL(selector) {
+    const list = functions.elementsL.call(this, selector)
+    return list.length ? list[0] : null
+  },
+
+  // The selector is a list of steps separated by ">>", each step searches inside the results of the previous one.
+  // The syntax of a step is "engine=value", available engines are css, xpath, text, role, label, placeholder,
+  // and testid. If no engine is specified, the step is a css selector, or xpath selector if it starts with "//".
+  elementsL(selector) {
+    let list = [functions.selectable(this)]
+    for (const step of functions.locatorSteps(selector)) {
+      const set = new Set()
+      for (const root of list) {
+        for (const el of functions.locatorQuery(root, step)) set.add(el)
+      }
+      list = Array.from(set)
+    }
+    return list
+  },
+
+  locatorSteps(selector) {
+    const steps = []
+    let quote = ''
+    let start = 0
+    for (let i = 0; i < selector.length; i++) {
+      const c = selector[i]
+      if (quote) {
+        if (c === '\\') i++
+        else if (c === quote) quote = ''
+      } else if (c === '"' || c === "'") {
+        quote = c
+      } else if (c === '>' && selector[i + 1] === '>') {
+        steps.push(selector.slice(start, i))
+        start = i + 2
+        i++
+      }
+    }
+    steps.push(selector.slice(start))
+
+    return steps.map((step) => {
+      step = step.trim()
+      const m = step.match(
+        /^(css|xpath|text|role|label|placeholder|testid)\s*=([\s\S]*)$/
+      )
+      if (m) return { engine: m[1], value: m[2].trim() }
+      if (step.startsWith('//')) return { engine: 'xpath', value: step }
+      return { engine: 'css', value: step }
+    })
+  },
+
+  locatorQuery(root, { engine, value }) {
+    const all = () => functions.deepElements(root)
+    const match = functions.locatorMatcher
+
+    switch (engine) {
+      case 'xpath': {
+        const doc = root.ownerDocument || root
+        const res = doc.evaluate(
+          value,
+          root,
+          null,
+          XPathResult.ORDERED_NODE_SNAPSHOT_TYPE
+        )
+        const list = []
+        for (let i = 0; i < res.snapshotLength; i++) {
+          list.push(res.snapshotItem(i))
+        }
+        return list
+      }
+
+      case 'text': {
+        const m = match(value)
+        const skip = ['SCRIPT', 'STYLE', 'NOSCRIPT', 'TEMPLATE', 'HEAD']
+        const list = all().filter(
+          (el) => !skip.includes(el.tagName) && m(functions.locatorText(el))
+        )
+        // only keep the innermost elements
+        return list.filter(
+          (el) => !list.some((c) => c !== el && el.contains(c))
+        )
+      }
+
+      case 'role': {
+        const m = value.match(/^([\w-]+)([\s\S]*)$/)
+        if (!m) throw new Error('invalid role selector: ' + value)
+        const attrs = []
+        const reg = /\[\s*([\w-]+)\s*(?:=\s*("(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'|\/(?:[^/\\]|\\.)+\/[a-z]*|[^\]]*?))?\s*\]/g
+        let a
+        while ((a = reg.exec(m[2]))) attrs.push(a)
+        return all().filter((el) => {
+          if (functions.ariaRole(el) !== m[1].toLowerCase()) return false
+          return attrs.every(([, name, v]) => {
+            switch (name) {
+              case 'name':
+                return match(v || '')(functions.accessibleName(el))
+              case 'level':
+                return functions.ariaLevel(el) === +v
+              default: {
+                const attr = el.getAttribute('aria-' + name)
+                const prop = el[name] === true ? 'true' : attr
+                return v === undefined
+                  ? prop === 'true'
+                  : match(v)(prop === null ? 'false' : prop)
+              }
+            }
+          })
+        })
+      }
+
+      case 'label': {
+        const m = match(value)
+        return all().filter(
+          (el) =>
+            functions.isLabelable(el) &&
+            functions.labelTexts(el).some((t) => m(t))
+        )
+      }
+
+      case 'placeholder': {
+        const m = match(value)
+        return all().filter(
+          (el) =>
+            el.hasAttribute('placeholder') &&
+            m(el.getAttribute('placeholder'))
+        )
+      }
+
+      case 'testid': {
+        const id = value.replace(/^(["'])([\s\S]*)\1$/, '$2')
+        return all().filter((el) => el.getAttribute('data-testid') === id)
+      }
+
+      default:
+        return all().filter((el) => el.matches(value))
+    }
+  },
+
+  // A value that is wrapped with quotes will match the whole text, one that is wrapped with "/"
+  // is a js regex, otherwise it matches the text that contains it case-insensitively.
+  // The whitespaces of the text will be normalized before matching.
+  locatorMatcher(value) {
+    const norm = (s) => (s || '').replace(/\s+/g, ' ').trim()
+
+    const reg = value.match(/^\/([\s\S]+)\/([a-z]*)$/)
+    if (reg) {
+      const r = new RegExp(reg[1], reg[2])
+      return (s) => r.test(norm(s))
+    }
+
+    const quoted = value.match(/^(["'])([\s\S]*)\1$/)
+    if (quoted) {
+      const v = norm(quoted[2].replace(/\\(.)/g, '$1'))
+      return (s) => norm(s) === v
+    }
+
+    const v = norm(value).toLowerCase()
+    return (s) => norm(s).toLowerCase().includes(v)
+  },
+
+  // all the descendant elements of the root, including the ones inside open shadow roots and same origin iframes
+  deepElements(root) {
+    const list = []
+    const walk = (node) => {
+      for (const el of node.querySelectorAll('*')) {
+        list.push(el)
+        if (el.shadowRoot) walk(el.shadowRoot)
+        if (el.tagName === 'IFRAME' || el.tagName === 'FRAME') {
+          let doc = null
+          try {
+            doc = el.contentDocument
+          } catch (e) {} // eslint-disable-line no-empty
+          if (doc) walk(doc)
+        }
+      }
+    }
+    if (root.shadowRoot) walk(root.shadowRoot)
+    walk(root)
+    return list
+  },
+
+  locatorText(el) {
+    if (el.tagName === 'INPUT' && /^(button|submit|reset)$/.test(el.type)) {
+      return el.value
+    }
+    return el.textContent
+  },
+
+  ariaRole(el) {
+    const role = (el.getAttribute('role') || '').trim().split(/\s+/)[0]
+    if (role) return role.toLowerCase()
+
+    switch (el.tagName) {
+      case 'A':
+      case 'AREA':
+        return el.hasAttribute('href') ? 'link' : ''
+      case 'BUTTON':
+        return 'button'
+      case 'INPUT':
+        switch (el.type) {
+          case 'button':
+          case 'submit':
+          case 'reset':
+          case 'image':
+            return 'button'
+          case 'checkbox':
+            return 'checkbox'
+          case 'radio':
+            return 'radio'
+          case 'range':
+            return 'slider'
+          case 'number':
+            return 'spinbutton'
+          case 'search':
+            return 'searchbox'
+          case 'hidden':
+            return ''
+          default:
+            return 'textbox'
+        }
+      case 'TEXTAREA':
+        return 'textbox'
+      case 'SELECT':
+        return el.multiple || el.size > 1 ? 'listbox' : 'combobox'
+      case 'OPTION':
+        return 'option'
+      case 'H1':
+      case 'H2':
+      case 'H3':
+      case 'H4':
+      case 'H5':
+      case 'H6':
+        return 'heading'
+      case 'IMG':
+        return el.getAttribute('alt') === '' ? 'presentation' : 'img'
+      case 'UL':
+      case 'OL':
+        return 'list'
+      case 'LI':
+        return 'listitem'
+      case 'NAV':
+        return 'navigation'
+      case 'MAIN':
+        return 'main'
+      case 'HEADER':
+        return 'banner'
+      case 'FOOTER':
+        return 'contentinfo'
+      case 'ASIDE':
+        return 'complementary'
+      case 'FORM':
+        return 'form'
+      case 'ARTICLE':
+        return 'article'
+      case 'DIALOG':
+        return 'dialog'
+      case 'TABLE':
+        return 'table'
+      case 'TR':
+        return 'row'
+      case 'TD':
+        return 'cell'
+      case 'TH':
+        return 'columnheader'
+      case 'P':
+        return 'paragraph'
+      case 'HR':
+        return 'separator'
+      case 'PROGRESS':
+        return 'progressbar'
+      default:
+        return ''
+    }
+  },
+
+  ariaLevel(el) {
+    const m = el.tagName.match(/^H([1-6])$/)
+    return m ? +m[1] : +el.getAttribute('aria-level')
+  },
+
+  accessibleName(el) {
+    const labels = functions.labelTexts(el)
+    if (labels.length) return labels.join(' ')
+
+    if (
+      el.tagName === 'IMG' ||
+      (el.tagName === 'INPUT' && el.type === 'image')
+    ) {
+      const alt = el.getAttribute('alt')
+      if (alt) return alt
+    }
+
+    const fromContent = [
+      'button',
+      'link',
+      'heading',
+      'option',
+      'listitem',
+      'cell',
+      'columnheader',
+      'row',
+      'tab',
+      'menuitem',
+      'checkbox',
+      'radio',
+      'switch',
+      'treeitem',
+      'tooltip'
+    ]
+    if (fromContent.includes(functions.ariaRole(el))) {
+      const text = functions.locatorText(el).trim()
+      if (text) return text
+    }
+
+    return el.getAttribute('title') || el.getAttribute('placeholder') || ''
+  },
+
+  isLabelable(el) {
+    const tags = [
+      'INPUT',
+      'TEXTAREA',
+      'SELECT',
+      'BUTTON',
+      'METER',
+      'OUTPUT',
+      'PROGRESS'
+    ]
+    return (
+      tags.includes(el.tagName) ||
+      el.isContentEditable ||
+      el.hasAttribute('aria-label') ||
+      el.hasAttribute('aria-labelledby')
+    )
+  },
+
+  // the texts from aria-labelledby, aria-label, and the label elements
+  labelTexts(el) {
+    const ids = el.getAttribute('aria-labelledby')
+    if (ids) {
+      const root = el.getRootNode()
+      const list = ids
+        .split(/\s+/)
+        .map((id) => root.getElementById && root.getElementById(id))
+        .filter((l) => l)
+        .map((l) => l.textContent)
+      if (list.length) return list
+    }
+
+    const label = el.getAttribute('aria-label')
+    if (label) return [label]
+
+    return Array.from(el.labels || []).map((l) => l.textContent)
+  },
+
   parents(selector) {
     let p = this.parentElement
     const list = []
@@ -0,0 +1,114 @@
+package launcher
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DefaultAllowFlags is the default allow-list of RemoteLauncher.AllowFlags,
+// none of them can be used to execute commands or access files on the server.
+var DefaultAllowFlags = []string{
+	"headless",
+	"window-size",
+	"lang",
+	"user-agent",
+	"proxy-server",
+	"proxy-bypass-list",
+	"auto-open-devtools-for-tabs",
+	flagXVFB,
+	flagLabel,
+}
+
+// RemoteOptions for NewRemote
+type RemoteOptions struct {
+	// Token for the bearer authentication of the RemoteLauncher
+	Token string
+
+	// TLS config for the https and wss urls, such as the RootCAs for a self-signed server,
+	// or the Certificates for the mTLS.
+	TLS *tls.Config
+}
+
+func (o *RemoteOptions) header() http.Header {
+	header := http.Header{}
+	if o.Token != "" {
+		header.Set("Authorization", "Bearer "+o.Token)
+	}
+	return header
+}
+
+func (o *RemoteOptions) httpClient() *http.Client {
+	if o.TLS == nil {
+		return http.DefaultClient
+	}
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: o.TLS}}
+}
+
+// returns true if the request has the Token
+func (p *RemoteLauncher) authorized(r *http.Request) bool {
+	if p.Token == "" {
+		return true
+	}
+
+	token := r.Header.Get("Authorization")
+	if !strings.HasPrefix(token, "Bearer ") {
+		return false
+	}
+	token = strings.TrimPrefix(token, "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(p.Token)) == 1
+}
+
+// checkFlags returns error if the flags of l that are different from the defaults are not allowed.
+// A new flag, a deleted default flag, and a changed default flag are all treated as different.
+// Unless the "user-data-dir" is allowed, the one from the client is replaced with a fresh dir of the server.
+func (p *RemoteLauncher) checkFlags(l *Launcher) error {
+	allow := map[string]bool{}
+	list := p.AllowFlags
+	if list == nil {
+		list = DefaultAllowFlags
+	}
+	for _, name := range list {
+		if name == "*" {
+			return nil
+		}
+		allow[l.normalizeFlag(name)] = true
+	}
+
+	defs := New().Flags
+
+	if !allow["user-data-dir"] {
+		if l.Flags == nil {
+			l.Flags = map[string][]string{}
+		}
+		l.Flags["user-data-dir"] = defs["user-data-dir"]
+	}
+
+	names := []string{}
+	for name := range l.Flags {
+		names = append(names, name)
+	}
+	for name := range defs {
+		if _, has := l.Flags[name]; !has {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value, has := l.Flags[name]
+		def, hasDef := defs[name]
+
+		if allow[name] || (has == hasDef && reflect.DeepEqual(value, def)) {
+			continue
+		}
+
+		return fmt.Errorf("flag is not allowed: %q", name)
+	}
+
+	return nil
+}
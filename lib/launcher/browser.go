@@ -9,7 +9,6 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
-	"strconv"
 	"strings"
 	"sync"
 
@@ -69,6 +68,18 @@ type Browser struct {
 
 	// Lock a tcp port to prevent race downloading. Default is 2968 .
 	Lock int
+
+	// Manifest of the pinned checksums to verify the downloaded zip, the default is a copy of the DefaultManifest.
+	// The download fails with ErrChecksum if the checksum of the Revision doesn't match.
+	// If the Manifest is supplied by you and has no entry for the Revision, the download fails with ErrNotPinned,
+	// the default one trusts the revisions it doesn't pin. Set it to nil to skip the verification.
+	Manifest Manifest
+
+	// AllowUnpinned trusts the download of the Revision that has no entry in the Manifest,
+	// its checksum will be added to the Manifest, so that you can save it to pin the revision.
+	AllowUnpinned bool
+
+	defaultManifest Manifest
 }
 
 // NewBrowser with default values
@@ -79,22 +90,36 @@ func NewBrowser() *Browser {
 		"linux":   filepath.Join(os.Getenv("HOME"), ".cache"),
 	}[runtime.GOOS]
 
+	hosts := []Host{HostGoogle, HostTaobao}
+	if defaults.Mirror != "" {
+		hosts = []Host{HostMirror(defaults.Mirror)}
+	}
+
+	manifest := DefaultManifest.clone()
+
 	return &Browser{
 		Context:  context.Background(),
 		Revision: DefaultRevision,
-		Hosts:    []Host{HostGoogle, HostTaobao},
+		Hosts:    hosts,
 		Dir:      filepath.Join(homeDir, "rod"),
 		Logger:   os.Stdout,
 		Lock:     defaults.Lock,
+		Manifest: manifest,
+
+		defaultManifest: manifest,
 	}
 }
 
 // Destination of the downloaded browser executable
 func (lc *Browser) Destination() string {
+	return lc.destination(lc.Revision)
+}
+
+func (lc *Browser) destination(revision int) string {
 	bin := map[string]string{
-		"darwin":  fmt.Sprintf("chromium-%d/chrome-mac/Chromium.app/Contents/MacOS/Chromium", lc.Revision),
-		"linux":   fmt.Sprintf("chromium-%d/chrome-linux/chrome", lc.Revision),
-		"windows": fmt.Sprintf("chromium-%d/chrome-win/chrome.exe", lc.Revision),
+		"darwin":  fmt.Sprintf("chromium-%d/chrome-mac/Chromium.app/Contents/MacOS/Chromium", revision),
+		"linux":   fmt.Sprintf("chromium-%d/chrome-linux/chrome", revision),
+		"windows": fmt.Sprintf("chromium-%d/chrome-win/chrome.exe", revision),
 	}[runtime.GOOS]
 
 	return filepath.Join(lc.Dir, bin)
@@ -108,23 +133,33 @@ func (lc *Browser) Download() (err error) {
 		}
 	}()
 
+	if _, has := lc.Manifest.Get(lc.Revision); lc.Manifest != nil && !has && lc.strict() {
+		return &ErrNotPinned{Revision: lc.Revision}
+	}
+
 	u, err := lc.fastestHost()
 	utils.E(err)
+	if u == "" {
+		return fmt.Errorf("no available host to download chromium-%d", lc.Revision)
+	}
 	return lc.download(lc.Context, u)
 }
 
+// the hosts that fail won't stop the others, it returns empty string if all of them fail
 func (lc *Browser) fastestHost() (fastest string, err error) {
 	setURL := sync.Once{}
 	ctx, cancel := context.WithCancel(lc.Context)
 	defer cancel()
 
+	wg := sync.WaitGroup{}
 	for _, host := range lc.Hosts {
 		u := host(lc.Revision)
 
+		wg.Add(1)
 		go func() {
 			defer func() {
 				_ = recover()
-				cancel()
+				wg.Done()
 			}()
 
 			q, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
@@ -134,18 +169,26 @@ func (lc *Browser) fastestHost() (fastest string, err error) {
 			utils.E(err)
 			defer func() { _ = res.Body.Close() }()
 
+			if res.StatusCode != http.StatusOK {
+				utils.E(fmt.Errorf("failed to download %s: %s", u, res.Status))
+			}
+
 			buf := make([]byte, 64*1024) // a TCP packet won't be larger than 64KB
 			_, err = res.Body.Read(buf)
 			utils.E(err)
 
 			setURL.Do(func() {
 				fastest = u
+				cancel()
 			})
 		}()
 	}
 
-	<-ctx.Done()
+	wg.Wait()
 
+	if fastest == "" {
+		err = lc.Context.Err()
+	}
 	return
 }
 
@@ -153,54 +196,120 @@ func (lc *Browser) download(ctx context.Context, u string) error {
 	_, _ = fmt.Fprintln(lc.Logger, "Download:", u)
 
 	zipPath := filepath.Join(lc.Dir, fmt.Sprintf("chromium-%d.zip", lc.Revision))
+	partPath := zipPath + ".part"
 
 	err := utils.Mkdir(lc.Dir)
 	utils.E(err)
 
-	zipFile, err := os.Create(zipPath)
+	// resume the download if the connection drops, give up if there's no progress
+	for {
+		n, err := lc.fetch(ctx, u, partPath)
+		if err == nil {
+			break
+		}
+		if n == 0 || ctx.Err() != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(lc.Logger, "Resume download:", err)
+	}
+
+	err = lc.verify(partPath)
+	if err != nil {
+		_ = os.Remove(partPath)
+		return err
+	}
+
+	err = os.Rename(partPath, zipPath)
 	utils.E(err)
 
+	unzipPath := filepath.Join(lc.Dir, fmt.Sprintf("chromium-%d", lc.Revision))
+	_ = os.RemoveAll(unzipPath)
+	utils.E(unzip(lc.Logger, zipPath, unzipPath))
+	return os.Remove(zipPath)
+}
+
+// fetch the url to the path, if the path exists it will resume from the end of it when the host supports
+// range requests. Returns the number of bytes received.
+func (lc *Browser) fetch(ctx context.Context, u, path string) (int64, error) {
+	var offset int64
+	if info, err := os.Stat(path); err == nil {
+		offset = info.Size()
+	}
+
 	q, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
-	utils.E(err)
+	if err != nil {
+		return 0, err
+	}
+	if offset > 0 {
+		q.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
 
 	res, err := lc.httpClient().Do(q)
-	utils.E(err)
+	if err != nil {
+		return 0, err
+	}
 	defer func() { _ = res.Body.Close() }()
 
-	size, err := strconv.ParseInt(res.Header.Get("Content-Length"), 10, 64)
-	utils.E(err)
+	flag := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	switch res.StatusCode {
+	case http.StatusOK:
+		offset = 0
+		flag |= os.O_TRUNC
+	case http.StatusPartialContent:
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the file is already complete, the verify and unzip will fail if it's not
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("failed to download %s: %s", u, res.Status)
+	}
+
+	f, err := os.OpenFile(path, flag, 0664)
+	if err != nil {
+		return 0, err
+	}
+
+	size := int64(-1)
+	if res.ContentLength >= 0 {
+		size = offset + res.ContentLength
+	}
 
 	progress := &progresser{
 		size:   int(size),
+		count:  int(offset),
 		logger: lc.Logger,
 	}
 
-	_, err = io.Copy(io.MultiWriter(progress, zipFile), res.Body)
-	utils.E(err)
-
-	err = zipFile.Close()
-	utils.E(err)
-
-	unzipPath := filepath.Join(lc.Dir, fmt.Sprintf("chromium-%d", lc.Revision))
-	_ = os.RemoveAll(unzipPath)
-	utils.E(unzip(lc.Logger, zipPath, unzipPath))
-	return os.Remove(zipPath)
+	n, err := io.Copy(io.MultiWriter(progress, f), res.Body)
+	if e := f.Close(); err == nil {
+		err = e
+	}
+	if err == nil && size >= 0 && offset+n != size {
+		err = fmt.Errorf("incomplete download, expected %d bytes, got %d: %w", size, offset+n, io.ErrUnexpectedEOF)
+	}
+	return n, err
 }
 
 func (lc *Browser) httpClient() *http.Client {
-	return &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	t := &http.Transport{DisableKeepAlives: true}
+	t.RegisterProtocol("file", fileTransport{})
+	return &http.Client{Transport: t}
 }
 
 // Get is a smart helper to get the browser executable path.
 // If Destination doesn't exists it will download the browser to Destination.
+// It records the last use time of the revision, check Browser.GC for details.
 func (lc *Browser) Get() (string, error) {
 	defer leakless.LockPort(lc.Lock)()
 
-	if lc.Exists() {
-		return lc.Destination(), nil
+	if !lc.Exists() {
+		err := lc.Download()
+		if err != nil {
+			return lc.Destination(), err
+		}
 	}
 
-	return lc.Destination(), lc.Download()
+	lc.touch()
+	return lc.Destination(), nil
 }
 
 // MustGet is similar with Get
@@ -0,0 +1,334 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ysmood/leakless"
+)
+
+// ErrRevisionNotFound is returned when no installed revision matches the constraint
+var ErrRevisionNotFound = errors.New("no installed revision matches the constraint")
+
+// matches the "chromium-{revision}" dir, and the "chromium-{revision}.zip" or "chromium-{revision}.zip.part" download
+var regCacheEntry = regexp.MustCompile(`^chromium-(\d+)(\.zip|\.zip\.part)?$`)
+
+// the file in the revision dir to record the last use time via its modification time
+const lastUsedFile = ".rod-last-used"
+
+// the dir in the revision dir to record the pids of the browsers launched from the revision, check Launcher.Launch
+const usersDir = ".rod-users"
+
+// Revision is an installed browser in the Browser.Dir
+type Revision struct {
+	Revision int `json:"revision"`
+
+	// Path of the dir of the revision
+	Path string `json:"path"`
+
+	// Size of the dir in bytes
+	Size int64 `json:"size"`
+
+	// LastUsed is the last time the Browser.Get returned the revision, it's the install time if it's never used.
+	LastUsed time.Time `json:"lastUsed"`
+
+	// InUse is true if a browser process the Launcher launched from the revision is still running
+	InUse bool `json:"inUse"`
+}
+
+// Revisions installed in the Dir, sorted from the newest revision to the oldest
+func (lc *Browser) Revisions() ([]*Revision, error) {
+	list, err := ioutil.ReadDir(lc.Dir)
+	if os.IsNotExist(err) {
+		return []*Revision{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	revisions := []*Revision{}
+	for _, info := range list {
+		ms := regCacheEntry.FindStringSubmatch(info.Name())
+		if ms == nil || ms[2] != "" || !info.IsDir() {
+			continue
+		}
+
+		r := &Revision{Path: filepath.Join(lc.Dir, info.Name()), LastUsed: info.ModTime()}
+		r.Revision, _ = strconv.Atoi(ms[1])
+
+		if stat, err := os.Stat(filepath.Join(r.Path, lastUsedFile)); err == nil {
+			r.LastUsed = stat.ModTime()
+		}
+		r.InUse = inUse(r.Path)
+
+		err = filepath.Walk(r.Path, func(_ string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			r.Size += info.Size()
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		revisions = append(revisions, r)
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision > revisions[j].Revision
+	})
+
+	return revisions, nil
+}
+
+// Resolve returns the newest installed revision that matches the constraint. The constraint is a comma separated
+// list of the conditions that must all be true, such as ">=850000,<900000". The operators are ">", ">=", "<", "<=",
+// and "=", a revision without operator is the same as "=". An empty constraint matches any revision.
+// Use it with the Revision field to share the Dir with the teams that pin different revisions:
+//
+//     if r, err := b.Resolve(">=850000"); err == nil {
+//         b.Revision = r.Revision
+//     }
+//
+func (lc *Browser) Resolve(constraint string) (*Revision, error) {
+	match, err := parseConstraint(constraint)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := lc.Revisions()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range list {
+		if _, err := os.Stat(lc.destination(r.Revision)); err == nil && match(r.Revision) {
+			return r, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrRevisionNotFound, constraint)
+}
+
+func parseConstraint(constraint string) (func(int) bool, error) {
+	conds := []func(int) bool{}
+
+	for _, str := range strings.Split(constraint, ",") {
+		str = strings.TrimSpace(str)
+		if str == "" {
+			continue
+		}
+
+		op := strings.TrimRight(str, "0123456789 ")
+		v, err := strconv.Atoi(strings.TrimSpace(str[len(op):]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid revision constraint: %q", str)
+		}
+
+		var cond func(int) bool
+		switch strings.TrimSpace(op) {
+		case ">":
+			cond = func(r int) bool { return r > v }
+		case ">=":
+			cond = func(r int) bool { return r >= v }
+		case "<":
+			cond = func(r int) bool { return r < v }
+		case "<=":
+			cond = func(r int) bool { return r <= v }
+		case "=", "":
+			cond = func(r int) bool { return r == v }
+		default:
+			return nil, fmt.Errorf("invalid revision constraint: %q", str)
+		}
+		conds = append(conds, cond)
+	}
+
+	return func(r int) bool {
+		for _, cond := range conds {
+			if !cond(r) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// GCOptions for Browser.GC
+type GCOptions struct {
+	// MaxAge removes the revisions that haven't been used for longer than it, 0 means no limit.
+	// The unfinished downloads older than it are removed too.
+	MaxAge time.Duration
+
+	// MaxCount keeps at most this number of the most recently used revisions, 0 means no limit.
+	MaxCount int
+
+	// Keep the revisions no matter what, the Revision of the Browser is always kept.
+	Keep []int
+}
+
+// GC removes the unused revisions in the Dir. It never removes the Revision of the Browser, the Keep ones,
+// and the ones that are InUse, so it's safe to run it while other processes use the same Dir, as long as they
+// launch the browser via the Launcher with the default bin and share the same Lock to prevent GC from racing with
+// their downloads. A browser that is launched from the Dir without the Launcher is not protected.
+// The InUse revisions are counted by the MaxCount. Returns the removed paths.
+func (lc *Browser) GC(opts GCOptions) ([]string, error) {
+	defer leakless.LockPort(lc.Lock)()
+
+	list, err := lc.Revisions()
+	if err != nil {
+		return nil, err
+	}
+
+	keep := append([]int{lc.Revision}, opts.Keep...)
+	now := time.Now()
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].LastUsed.After(list[j].LastUsed)
+	})
+
+	remove := []string{}
+	kept := 0
+	for _, r := range list {
+		if containsInt(keep, r.Revision) || r.InUse {
+			kept++
+			continue
+		}
+
+		if (opts.MaxAge > 0 && now.Sub(r.LastUsed) > opts.MaxAge) || (opts.MaxCount > 0 && kept >= opts.MaxCount) {
+			remove = append(remove, r.Path)
+			continue
+		}
+		kept++
+	}
+
+	if opts.MaxAge > 0 {
+		downloads, err := lc.downloads()
+		if err != nil {
+			return nil, err
+		}
+		for _, info := range downloads {
+			if now.Sub(info.ModTime()) > opts.MaxAge {
+				remove = append(remove, filepath.Join(lc.Dir, info.Name()))
+			}
+		}
+	}
+
+	return removeAll(remove)
+}
+
+// Prune removes the revisions in the Dir except the keep ones, the unfinished downloads of the other revisions
+// are removed too. Unlike the GC, it removes the revisions that are InUse. Returns the removed paths.
+func (lc *Browser) Prune(keep ...int) ([]string, error) {
+	defer leakless.LockPort(lc.Lock)()
+
+	list, err := ioutil.ReadDir(lc.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	remove := []string{}
+	for _, info := range list {
+		ms := regCacheEntry.FindStringSubmatch(info.Name())
+		if ms == nil {
+			continue
+		}
+
+		revision, _ := strconv.Atoi(ms[1])
+		if containsInt(keep, revision) {
+			continue
+		}
+
+		remove = append(remove, filepath.Join(lc.Dir, info.Name()))
+	}
+
+	return removeAll(remove)
+}
+
+// the unfinished downloads in the Dir
+func (lc *Browser) downloads() ([]os.FileInfo, error) {
+	list, err := ioutil.ReadDir(lc.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	downloads := []os.FileInfo{}
+	for _, info := range list {
+		if ms := regCacheEntry.FindStringSubmatch(info.Name()); ms != nil && ms[2] != "" {
+			downloads = append(downloads, info)
+		}
+	}
+	return downloads, nil
+}
+
+// record the last use time of the revision
+func (lc *Browser) touch() {
+	p := filepath.Join(lc.Dir, fmt.Sprintf("chromium-%d", lc.Revision), lastUsedFile)
+	now := time.Now()
+	if os.Chtimes(p, now, now) != nil {
+		_ = ioutil.WriteFile(p, nil, 0664)
+	}
+}
+
+// record the browser process as a user of the revision, returns the path of the record to remove after
+// the process exits
+func (lc *Browser) register(pid int) string {
+	dir := filepath.Join(lc.Dir, fmt.Sprintf("chromium-%d", lc.Revision), usersDir)
+	p := filepath.Join(dir, strconv.Itoa(pid))
+	if os.MkdirAll(dir, 0775) == nil && ioutil.WriteFile(p, nil, 0664) == nil {
+		return p
+	}
+	return ""
+}
+
+// returns true if any browser process that registered the revision dir is still running,
+// the stale records are removed
+func inUse(path string) bool {
+	list, err := ioutil.ReadDir(filepath.Join(path, usersDir))
+	if err != nil {
+		return false
+	}
+
+	used := false
+	for _, info := range list {
+		pid, err := strconv.Atoi(info.Name())
+		if err == nil && processAlive(pid) {
+			used = true
+			continue
+		}
+		_ = os.Remove(filepath.Join(path, usersDir, info.Name()))
+	}
+	return used
+}
+
+func removeAll(list []string) ([]string, error) {
+	removed := []string{}
+	for _, p := range list {
+		err := os.RemoveAll(p)
+		if err != nil {
+			return removed, err
+		}
+		removed = append(removed, p)
+	}
+	return removed, nil
+}
+
+func containsInt(list []int, i int) bool {
+	for _, v := range list {
+		if v == i {
+			return true
+		}
+	}
+	return false
+}
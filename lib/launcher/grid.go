@@ -0,0 +1,333 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-rod/rod/lib/utils"
+)
+
+// ErrQueueTimeout is returned when a request waits too long for a free browser slot
+var ErrQueueTimeout = errors.New("timeout waiting for a free browser slot")
+
+// RemoteSession is a browser launched by the RemoteLauncher for a websocket connection
+type RemoteSession struct {
+	ID string `json:"id"`
+
+	// PID of the browser process
+	PID int `json:"pid"`
+
+	// Client is the remote address of the websocket connection
+	Client string `json:"client"`
+
+	// Labels set by Launcher.Label
+	Labels map[string]string `json:"labels"`
+
+	// Warm is true if the browser is pre-launched
+	Warm bool `json:"warm"`
+
+	Start time.Time `json:"start"`
+
+	// Uptime in seconds
+	Uptime float64 `json:"uptime"`
+
+	l *Launcher
+}
+
+// RemoteStatus of the RemoteLauncher
+type RemoteStatus struct {
+	Max      int              `json:"max"`
+	Running  int              `json:"running"`
+	Queued   int              `json:"queued"`
+	Warm     int              `json:"warm"`
+	Launched int64            `json:"launched"`
+	Timeouts int64            `json:"timeouts"`
+	Sessions []*RemoteSession `json:"sessions"`
+}
+
+type warmBrowser struct {
+	l *Launcher
+	u string
+}
+
+func (p *RemoteLauncher) setup() {
+	p.sessions = map[string]*RemoteSession{}
+	p.warm = make(chan *warmBrowser, p.Warm)
+	for i := 0; i < p.Warm; i++ {
+		go p.fillWarm()
+	}
+}
+
+// WarmUp launches the warm browsers now, by default they are launched on the first request
+func (p *RemoteLauncher) WarmUp() {
+	p.once.Do(p.setup)
+}
+
+// Status of the running sessions, sorted by the start time
+func (p *RemoteLauncher) Status() *RemoteStatus {
+	p.once.Do(p.setup)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	s := &RemoteStatus{
+		Max:      p.Max,
+		Running:  len(p.sessions),
+		Queued:   len(p.queue),
+		Warm:     len(p.warm),
+		Launched: atomic.LoadInt64(&p.launched),
+		Timeouts: atomic.LoadInt64(&p.timeouts),
+		Sessions: []*RemoteSession{},
+	}
+
+	now := time.Now()
+	for _, session := range p.sessions {
+		clone := *session
+		clone.Uptime = now.Sub(session.Start).Seconds()
+		s.Sessions = append(s.Sessions, &clone)
+	}
+	sort.Slice(s.Sessions, func(i, j int) bool {
+		return s.Sessions[i].Start.Before(s.Sessions[j].Start)
+	})
+
+	return s
+}
+
+// Kill the session with the id, returns false if the session doesn't exist
+func (p *RemoteLauncher) Kill(id string) bool {
+	p.once.Do(p.setup)
+
+	p.lock.Lock()
+	session, has := p.sessions[id]
+	p.lock.Unlock()
+
+	if !has {
+		return false
+	}
+
+	p.Logger.Println("Kill session", id)
+	session.l.Kill()
+	return true
+}
+
+// Close kills all the running sessions and the warm browsers
+func (p *RemoteLauncher) Close() {
+	p.once.Do(p.setup)
+
+	p.lock.Lock()
+	p.closed = true
+	ids := []string{}
+	for id := range p.sessions {
+		ids = append(ids, id)
+	}
+	p.lock.Unlock()
+
+	for _, id := range ids {
+		p.Kill(id)
+	}
+
+	for {
+		select {
+		case wb := <-p.warm:
+			wb.l.Kill()
+			wb.l.Cleanup()
+		default:
+			return
+		}
+	}
+}
+
+// acquire a slot for a browser, the requests are served in FIFO order
+func (p *RemoteLauncher) acquire(ctx context.Context) error {
+	p.lock.Lock()
+	if p.Max <= 0 || (p.running < p.Max && len(p.queue) == 0) {
+		p.running++
+		p.lock.Unlock()
+		return nil
+	}
+
+	ch := make(chan struct{})
+	p.queue = append(p.queue, ch)
+	p.lock.Unlock()
+
+	var timeout <-chan time.Time
+	if p.QueueTimeout > 0 {
+		t := time.NewTimer(p.QueueTimeout)
+		defer t.Stop()
+		timeout = t.C
+	}
+
+	var err error
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timeout:
+		atomic.AddInt64(&p.timeouts, 1)
+		err = ErrQueueTimeout
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for i, c := range p.queue {
+		if c == ch {
+			p.queue = append(p.queue[:i], p.queue[i+1:]...)
+			return err
+		}
+	}
+
+	// the slot is handed over before we remove ourselves from the queue, pass it to the next one
+	p.releaseLocked()
+	return err
+}
+
+func (p *RemoteLauncher) release() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.releaseLocked()
+}
+
+func (p *RemoteLauncher) releaseLocked() {
+	if len(p.queue) > 0 {
+		close(p.queue[0])
+		p.queue = p.queue[1:]
+		return
+	}
+	p.running--
+}
+
+func (p *RemoteLauncher) addSession(l *Launcher, client string, labels []string, warm bool) *RemoteSession {
+	atomic.AddInt64(&p.launched, 1)
+
+	session := &RemoteSession{
+		ID:     utils.RandString(8),
+		PID:    l.PID(),
+		Client: client,
+		Labels: map[string]string{},
+		Warm:   warm,
+		Start:  time.Now(),
+		l:      l,
+	}
+	for _, label := range labels {
+		kv := strings.SplitN(label, "=", 2)
+		if len(kv) == 2 {
+			session.Labels[kv[0]] = kv[1]
+		} else {
+			session.Labels[kv[0]] = ""
+		}
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.sessions[session.ID] = session
+	return session
+}
+
+func (p *RemoteLauncher) removeSession(s *RemoteSession) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	delete(p.sessions, s.ID)
+}
+
+// returns a warm browser if the launch options are the same as the default ones
+func (p *RemoteLauncher) takeWarm(l *Launcher) *warmBrowser {
+	if p.Warm <= 0 || !warmable(l) {
+		return nil
+	}
+
+	select {
+	case wb := <-p.warm:
+		go p.fillWarm()
+		return wb
+	default:
+		return nil
+	}
+}
+
+func (p *RemoteLauncher) fillWarm() {
+	l := New().Leakless(false)
+	u, err := l.Launch()
+	if err != nil {
+		p.Logger.Println("Failed to launch warm browser:", err)
+		return
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if !p.closed {
+		select {
+		case p.warm <- &warmBrowser{l, u}:
+			p.Logger.Println("Warm", u)
+			return
+		default:
+		}
+	}
+
+	go func() {
+		l.Kill()
+		l.Cleanup()
+	}()
+}
+
+// the user-data-dir is random for each launcher, the rod flags don't affect the browser
+func warmable(l *Launcher) bool {
+	normalize := func(flags map[string][]string) map[string][]string {
+		m := map[string][]string{}
+		for k, v := range flags {
+			if k == "user-data-dir" || strings.HasPrefix(k, "rod-") {
+				continue
+			}
+			m[k] = v
+		}
+		return m
+	}
+
+	if _, has := l.Get(flagKeepUserDataDir); has {
+		return false
+	}
+
+	return reflect.DeepEqual(normalize(l.Flags), normalize(New().Flags))
+}
+
+func (p *RemoteLauncher) status(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	utils.E(w.Write(utils.MustToJSONBytes(p.Status())))
+}
+
+func (p *RemoteLauncher) metrics(w http.ResponseWriter, _ *http.Request) {
+	s := p.Status()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	for _, m := range []struct {
+		name, typ, help string
+		value           interface{}
+	}{
+		{"rod_launcher_max", "gauge", "Max number of the concurrent browsers", s.Max},
+		{"rod_launcher_sessions", "gauge", "Number of the running sessions", s.Running},
+		{"rod_launcher_queued", "gauge", "Number of the requests waiting in the queue", s.Queued},
+		{"rod_launcher_warm", "gauge", "Number of the idle warm browsers", s.Warm},
+		{"rod_launcher_launched_total", "counter", "Total number of the sessions", s.Launched},
+		{"rod_launcher_queue_timeouts_total", "counter", "Total number of the queue timeouts", s.Timeouts},
+	} {
+		utils.E(fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %v\n", m.name, m.help, m.name, m.typ, m.name, m.value))
+	}
+}
+
+func (p *RemoteLauncher) kill(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/sessions/")
+	if !p.Kill(id) {
+		http.Error(w, "session not found: "+id, http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
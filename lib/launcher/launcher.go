@@ -10,8 +10,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 
+	"github.com/go-rod/rod/lib/cdp"
 	"github.com/go-rod/rod/lib/defaults"
 	"github.com/go-rod/rod/lib/utils"
 	"github.com/ysmood/leakless"
@@ -25,18 +27,20 @@ const (
 
 // Launcher is a helper to launch browser binary smartly
 type Launcher struct {
-	logger    io.Writer
-	ctx       context.Context
-	ctxCancel func()
-	browser   *Browser
-	bin       string
-	url       string
-	parser    *URLParser
-	Flags     map[string][]string `json:"flags"`
-	pid       int
-	exit      chan struct{}
-	remote    bool // remote mode or not
-	leakless  bool
+	logger     io.Writer
+	ctx        context.Context
+	ctxCancel  func()
+	browser    *Browser
+	bin        string
+	url        string
+	parser     *URLParser
+	Flags      map[string][]string `json:"flags"`
+	pid        int
+	exit       chan struct{}
+	record     string // the record of the pid in the revision dir, check Browser.GC
+	remote     bool   // remote mode or not
+	remoteOpts *RemoteOptions
+	leakless   bool
 }
 
 // New returns the default arguments to start browser.
@@ -260,7 +264,8 @@ func (l *Launcher) ProfileDir(dir string) *Launcher {
 // If it's not zero, the launcher will try to connect to it before starting a new browser process.
 // For example, to reuse the same browser process for between 2 runs of a Go program, you can
 // do something like:
-//     launcher.New().RemoteDebuggingPort(9222).MustLaunch()
+//
+//	launcher.New().RemoteDebuggingPort(9222).MustLaunch()
 //
 // Related doc: https://chromedevtools.github.io/devtools-protocol/
 func (l *Launcher) RemoteDebuggingPort(port int) *Launcher {
@@ -373,8 +378,11 @@ func (l *Launcher) Launch() (string, error) {
 		}
 	}
 
+	l.register()
+
 	go func() {
 		_ = cmd.Wait()
+		l.unregister()
 		close(l.exit)
 	}()
 
@@ -387,6 +395,76 @@ func (l *Launcher) Launch() (string, error) {
 	return ResolveURL(u)
 }
 
+// MustLaunchPipe is similar to LaunchPipe
+func (l *Launcher) MustLaunchPipe() *cdp.Client {
+	c, err := l.LaunchPipe()
+	utils.E(err)
+	return c
+}
+
+// LaunchPipe launches the browser with the "--remote-debugging-pipe" flag, no debug port will be opened.
+// It returns a cdp client that talks to the browser via the pipes, use it like:
+//
+//	rod.New().Client(launcher.New().MustLaunchPipe()).MustConnect()
+//
+// Leakless is not supported in this mode, because the pipes can't be passed through the guard process.
+// It's not supported on Windows.
+func (l *Launcher) LaunchPipe() (*cdp.Client, error) {
+	defer l.ctxCancel()
+
+	if runtime.GOOS == "windows" {
+		return nil, errors.New("pipe mode is not supported on windows")
+	}
+
+	bin, err := l.getBin()
+	if err != nil {
+		return nil, err
+	}
+
+	l.Delete("remote-debugging-port")
+	l.Set("remote-debugging-pipe")
+
+	// the browser reads from fd 3 and writes to fd 4
+	fd3r, fd3w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	fd4r, fd4w, err := os.Pipe()
+	if err != nil {
+		_ = fd3r.Close()
+		_ = fd3w.Close()
+		return nil, err
+	}
+
+	cmd := exec.Command(bin, l.FormatArgs()...)
+	l.setupCmd(cmd)
+	cmd.ExtraFiles = []*os.File{fd3r, fd4w}
+
+	err = cmd.Start()
+
+	// the browser process has its own copies
+	_ = fd3r.Close()
+	_ = fd4w.Close()
+
+	if err != nil {
+		_ = fd3w.Close()
+		_ = fd4r.Close()
+		return nil, err
+	}
+
+	l.pid = cmd.Process.Pid
+
+	l.register()
+
+	go func() {
+		_ = cmd.Wait()
+		l.unregister()
+		close(l.exit)
+	}()
+
+	return cdp.New("").Websocket(cdp.NewPipe(fd3w, fd4r)), nil
+}
+
 func (l *Launcher) setupCmd(cmd *exec.Cmd) {
 	l.osSetupCmd(cmd)
 
@@ -407,6 +485,19 @@ func (l *Launcher) getBin() (string, error) {
 	return l.bin, nil
 }
 
+// register the browser process as a user of the revision if the bin is from the Browser
+func (l *Launcher) register() {
+	if l.bin == "" {
+		l.record = l.browser.register(l.pid)
+	}
+}
+
+func (l *Launcher) unregister() {
+	if l.record != "" {
+		_ = os.Remove(l.record)
+	}
+}
+
 func (l *Launcher) getURL() (u string, err error) {
 	select {
 	case <-l.ctx.Done():
@@ -425,6 +516,36 @@ func (l *Launcher) PID() int {
 	return l.pid
 }
 
+// Exit returns a channel that will be closed when the launched browser process exits
+func (l *Launcher) Exit() <-chan struct{} {
+	return l.exit
+}
+
+// Clone returns a new launcher with the same options that hasn't launched yet,
+// such as to launch the browser again after it exits. The user-data-dir is shared.
+func (l *Launcher) Clone() *Launcher {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	flags := map[string][]string{}
+	for k, v := range l.Flags {
+		flags[k] = append([]string(nil), v...)
+	}
+
+	return &Launcher{
+		ctx:        ctx,
+		ctxCancel:  cancel,
+		Flags:      flags,
+		exit:       make(chan struct{}),
+		browser:    l.browser,
+		bin:        l.bin,
+		parser:     NewURLParser(),
+		remote:     l.remote,
+		remoteOpts: l.remoteOpts,
+		leakless:   l.leakless,
+		logger:     l.logger,
+	}
+}
+
 // Kill the browser process
 func (l *Launcher) Kill() {
 	// TODO: If kill too fast, the browser's children processes may not be ready.
@@ -440,12 +561,16 @@ func (l *Launcher) Kill() {
 	if err == nil {
 		_ = p.Kill()
 	}
+
+	l.unregister()
 }
 
 // Cleanup wait until the Browser exits and remove UserDataDir
 func (l *Launcher) Cleanup() {
 	<-l.exit
 
+	l.unregister()
+
 	dir, _ := l.Get("user-data-dir")
 	_ = os.RemoveAll(dir)
 }
@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -39,23 +40,7 @@ func (t T) DownloadHosts() {
 func (t T) Download() {
 	s := t.Serve()
 	s.Mux.HandleFunc("/fast/", func(rw http.ResponseWriter, r *http.Request) {
-		buf := bytes.NewBuffer(nil)
-		zw := zip.NewWriter(buf)
-
-		// folder "to"
-		h := &zip.FileHeader{Name: "to/"}
-		h.SetMode(0755)
-		_, err := zw.CreateHeader(h)
-		t.E(err)
-
-		// file "file.txt"
-		w, err := zw.CreateHeader(&zip.FileHeader{Name: "to/file.txt"})
-		t.E(err)
-		b := []byte(strings.Repeat("test", 1000))
-		t.E(w.Write(b))
-
-		t.E(zw.Close())
-
+		buf := bytes.NewBuffer(testZip(t))
 		rw.Header().Add("Content-Length", fmt.Sprintf("%d", buf.Len()))
 		t.E(io.Copy(rw, buf))
 	})
@@ -73,10 +58,182 @@ func (t T) Download() {
 	defer cancel()
 	b.Hosts = []launcher.Host{launcher.HostTest(s.URL("/slow")), launcher.HostTest(s.URL("/fast"))}
 	b.Dir = filepath.Join("tmp", "browser-from-mirror", t.Srand(16))
+	b.Manifest = nil
 	t.E(b.Download())
 	t.Nil(os.Stat(b.Dir))
 }
 
+func (t T) DownloadMirror() {
+	dir, err := filepath.Abs(filepath.Join("tmp", "mirror", t.Srand(16)))
+	t.E(err)
+	data := testZip(t)
+	zipPath := filepath.FromSlash(strings.TrimPrefix(launcher.HostMirror(dir)(1), dir+"/"))
+	t.E(os.MkdirAll(filepath.Join(dir, filepath.Dir(zipPath)), 0755))
+	t.E(ioutil.WriteFile(filepath.Join(dir, zipPath), data, 0644))
+
+	fileURL := (&url.URL{Scheme: "file", Path: filepath.ToSlash(dir)}).String()
+
+	b, cancel := newBrowser()
+	b.Logger = ioutil.Discard
+	defer cancel()
+	b.Revision = 1
+	b.Hosts = []launcher.Host{launcher.HostMirror(fileURL + "/")}
+	b.Dir = filepath.Join("tmp", "browser-from-mirror", t.Srand(16))
+
+	// the default manifest trusts the revision it doesn't pin
+	t.E(b.Download())
+	t.Nil(os.Stat(filepath.Join(b.Dir, "chromium-1", "to", "file.txt")))
+
+	b.Dir = filepath.Join("tmp", "browser-from-mirror", t.Srand(16))
+	b.Manifest = launcher.Manifest{}
+	_, ok := b.Download().(*launcher.ErrNotPinned)
+	t.True(ok)
+	t.Err(os.Stat(b.Dir))
+
+	b.AllowUnpinned = true
+	t.E(b.Download())
+	t.Nil(os.Stat(filepath.Join(b.Dir, "chromium-1", "to", "file.txt")))
+
+	// the checksum is pinned
+	sum, has := b.Manifest.Get(1)
+	t.True(has)
+	t.Len(sum, 64)
+
+	b.Manifest.Set(1, strings.Repeat("0", 64))
+	err = b.Download()
+	t.Has(err.Error(), "sha256 checksum mismatch of chromium-1")
+	e, ok := err.(*launcher.ErrChecksum)
+	t.True(ok)
+	t.Eq(e.Actual, sum)
+
+	b.Hosts = []launcher.Host{launcher.HostMirror(fileURL + "/not-exists")}
+	t.Eq(b.Download().Error(), "no available host to download chromium-1")
+
+	p := filepath.Join("tmp", "manifest", t.Srand(16)+".json")
+	t.E(b.Manifest.Save(p))
+	m, err := launcher.LoadManifest(p)
+	t.E(err)
+	t.Eq(b.Manifest, m)
+	_, err = launcher.LoadManifest(p + ".not-exists")
+	t.Err(err)
+}
+
+func (t T) DownloadResume() {
+	data := testZip(t)
+	ranges := []string{}
+
+	s := t.Serve()
+	s.Mux.HandleFunc("/", func(rw http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(rw, r, "", time.Time{}, bytes.NewReader(data))
+	})
+
+	b, cancel := newBrowser()
+	b.Logger = ioutil.Discard
+	defer cancel()
+	b.Revision = 2
+	b.Hosts = []launcher.Host{launcher.HostMirror(s.URL())}
+	b.Manifest = nil
+	b.Dir = filepath.Join("tmp", "browser-from-mirror", t.Srand(16))
+
+	// an unfinished download
+	t.E(os.MkdirAll(b.Dir, 0755))
+	t.E(ioutil.WriteFile(filepath.Join(b.Dir, "chromium-2.zip.part"), data[:len(data)/2], 0644))
+
+	t.E(b.Download())
+	t.Eq(ranges[len(ranges)-1], fmt.Sprintf("bytes=%d-", len(data)/2))
+	t.Nil(os.Stat(filepath.Join(b.Dir, "chromium-2", "to", "file.txt")))
+	t.Err(os.Stat(filepath.Join(b.Dir, "chromium-2.zip.part")))
+}
+
+func (t T) BrowserPrune() {
+	b := launcher.NewBrowser()
+	b.Dir = filepath.Join("tmp", "browser-prune", t.Srand(16))
+
+	removed, err := b.Prune()
+	t.E(err)
+	t.Len(removed, 0)
+
+	for _, name := range []string{"chromium-1", "chromium-2", "chromium-3.zip.part", "other"} {
+		t.E(os.MkdirAll(filepath.Join(b.Dir, name), 0755))
+	}
+
+	removed, err = b.Prune(2)
+	t.E(err)
+	t.Eq([]string{filepath.Join(b.Dir, "chromium-1"), filepath.Join(b.Dir, "chromium-3.zip.part")}, removed)
+	t.Nil(os.Stat(filepath.Join(b.Dir, "chromium-2")))
+	t.Nil(os.Stat(filepath.Join(b.Dir, "other")))
+}
+
+func (t T) BrowserCache() {
+	b := launcher.NewBrowser()
+	b.Dir = filepath.Join("tmp", "browser-cache", t.Srand(16))
+
+	list, err := b.Revisions()
+	t.E(err)
+	t.Len(list, 0)
+
+	old := time.Now().Add(-time.Hour)
+	for _, r := range []int{800, 900, 1000} {
+		b.Revision = r
+		p := b.Destination()
+		t.E(os.MkdirAll(filepath.Dir(p), 0755))
+		t.E(ioutil.WriteFile(p, []byte("bin"), 0755))
+		t.E(os.Chtimes(filepath.Join(b.Dir, fmt.Sprintf("chromium-%d", r)), old, old))
+	}
+	t.E(ioutil.WriteFile(filepath.Join(b.Dir, "chromium-1100.zip.part"), nil, 0644))
+	t.E(os.Chtimes(filepath.Join(b.Dir, "chromium-1100.zip.part"), old, old))
+
+	// mark the 900 as used by a running process
+	b.Revision = 900
+	t.Eq(b.MustGet(), b.Destination())
+	t.E(os.MkdirAll(filepath.Join(b.Dir, "chromium-900", ".rod-users"), 0755))
+	t.E(ioutil.WriteFile(filepath.Join(b.Dir, "chromium-900", ".rod-users", strconv.Itoa(os.Getpid())), nil, 0644))
+
+	// a stale record of an exited process
+	stale := filepath.Join(b.Dir, "chromium-1000", ".rod-users", "999999999")
+	t.E(os.MkdirAll(filepath.Dir(stale), 0755))
+	t.E(ioutil.WriteFile(stale, nil, 0644))
+	t.E(os.Chtimes(filepath.Join(b.Dir, "chromium-1000"), old, old))
+
+	list, err = b.Revisions()
+	t.E(err)
+	t.Len(list, 3)
+	t.Eq(1000, list[0].Revision)
+	t.Gt(list[0].Size, int64(0))
+	t.Lt(list[0].LastUsed.Unix(), time.Now().Add(-time.Minute).Unix())
+	t.Gt(list[1].LastUsed.Unix(), time.Now().Add(-time.Minute).Unix())
+	t.False(list[0].InUse)
+	t.True(list[1].InUse)
+	t.Err(os.Stat(stale))
+
+	r, err := b.Resolve(">=850")
+	t.E(err)
+	t.Eq(1000, r.Revision)
+	r, err = b.Resolve(">= 850, <1000")
+	t.E(err)
+	t.Eq(900, r.Revision)
+	r, err = b.Resolve("800")
+	t.E(err)
+	t.Eq(800, r.Revision)
+	_, err = b.Resolve(">1000")
+	t.Is(err, launcher.ErrRevisionNotFound)
+	_, err = b.Resolve("~1")
+	t.Has(err.Error(), "invalid revision constraint")
+
+	// the 900 is recently used, the 1000 is kept by the MaxCount
+	removed, err := b.GC(launcher.GCOptions{MaxCount: 2})
+	t.E(err)
+	t.Eq([]string{filepath.Join(b.Dir, "chromium-800")}, removed)
+
+	// the 900 is in use by the current process even if it's not recently used
+	t.E(os.Chtimes(filepath.Join(b.Dir, "chromium-900", ".rod-last-used"), old, old))
+	b.Revision = 1
+	removed, err = b.GC(launcher.GCOptions{MaxAge: time.Minute, Keep: []int{1000}})
+	t.E(err)
+	t.Eq([]string{filepath.Join(b.Dir, "chromium-1100.zip.part")}, removed)
+}
+
 func (t T) BrowserGet() {
 	t.Nil(os.Stat(launcher.NewBrowser().MustGet()))
 }
@@ -109,6 +266,20 @@ func (t T) Launch() {
 	}
 }
 
+func (t T) LaunchPipe() {
+	l := launcher.New()
+	defer l.Kill()
+
+	client := l.MustLaunchPipe()
+	_, has := l.Get("remote-debugging-port")
+	t.False(has)
+
+	t.E(client.Connect(t.Context()))
+	res, err := client.Call(t.Context(), "", "Browser.getVersion", nil)
+	t.E(err)
+	t.Has(string(res), "protocolVersion")
+}
+
 func (t T) LaunchUserMode() {
 	l := launcher.NewUserMode()
 	defer l.Kill()
@@ -176,6 +347,26 @@ func (t T) LaunchErr() {
 	}
 }
 
+func testZip(t T) []byte {
+	buf := bytes.NewBuffer(nil)
+	zw := zip.NewWriter(buf)
+
+	// folder "to"
+	h := &zip.FileHeader{Name: "to/"}
+	h.SetMode(0755)
+	_, err := zw.CreateHeader(h)
+	t.E(err)
+
+	// file "file.txt"
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: "to/file.txt"})
+	t.E(err)
+	b := []byte(strings.Repeat("test", 1000))
+	t.E(w.Write(b))
+
+	t.E(zw.Close())
+	return buf.Bytes()
+}
+
 func newBrowser() (*launcher.Browser, func()) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 	b := launcher.NewBrowser()
@@ -204,3 +395,20 @@ func (t T) ProfileDir() {
 	t.E(err)
 	t.True(file.IsDir())
 }
+
+func (t T) LauncherClone() {
+	l := launcher.New().Set("test", "a")
+	c := l.Clone().Set("test", "b")
+
+	v, _ := l.Get("test")
+	t.Eq(v, "a")
+	v, _ = c.Get("test")
+	t.Eq(v, "b")
+	t.Eq(c.PID(), 0)
+
+	select {
+	case <-c.Exit():
+		t.Fail()
+	default:
+	}
+}
@@ -0,0 +1,7 @@
+// generated by "lib/launcher/manifest"
+
+package launcher
+
+// DefaultManifest of the DefaultRevision for each platform, it's the default Manifest of the Browser.
+// The Browser trusts the download of the revision that has no entry in it.
+var DefaultManifest = Manifest{}
@@ -0,0 +1,82 @@
+// Generates the DefaultManifest of the DefaultRevision, it downloads the zips of all the platforms
+// from the HostGoogle to calculate their checksums. It needs the network, run it manually from the root of the repo
+// after the DefaultRevision is updated:
+//
+//     go run ./lib/launcher/manifest
+//
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/utils"
+)
+
+var slash = filepath.FromSlash
+
+// the same as the hostConf of the launcher for each platform
+var platforms = []struct {
+	urlPrefix string
+	zipName   string
+}{
+	{"Linux_x64", "chrome-linux.zip"},
+	{"Mac", "chrome-mac.zip"},
+	{"Win", "chrome-win.zip"},
+}
+
+func main() {
+	pinned := true
+	for _, p := range platforms {
+		_, has := launcher.DefaultManifest[fmt.Sprintf("%s/%d", p.urlPrefix, launcher.DefaultRevision)]
+		pinned = pinned && has
+	}
+	if pinned {
+		return
+	}
+
+	entries := []string{}
+	for _, p := range platforms {
+		key := fmt.Sprintf("%s/%d", p.urlPrefix, launcher.DefaultRevision)
+		u := fmt.Sprintf("https://storage.googleapis.com/chromium-browser-snapshots/%s/%s", key, p.zipName)
+		entries = append(entries, fmt.Sprintf("\t%q: %q,", key, sum(u)))
+	}
+
+	build := utils.S(`// generated by "lib/launcher/manifest"
+
+package launcher
+
+// DefaultManifest of the DefaultRevision for each platform, it's the default Manifest of the Browser.
+// The Browser trusts the download of the revision that has no entry in it.
+var DefaultManifest = Manifest{
+{{.entries}}
+}
+`,
+		"entries", strings.Join(entries, "\n"),
+	)
+
+	utils.E(utils.OutputFile(slash("lib/launcher/manifest.go"), build))
+}
+
+func sum(u string) string {
+	fmt.Println("Download:", u)
+
+	res, err := http.Get(u)
+	utils.E(err)
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode != http.StatusOK {
+		utils.E(fmt.Errorf("failed to download %s: %s", u, res.Status))
+	}
+
+	h := sha256.New()
+	_, err = io.Copy(h, res.Body)
+	utils.E(err)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,212 @@
+package launcher
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/go-rod/rod/lib/utils"
+)
+
+// HostMirror returns a Host for the mirror that has the same layout as the HostGoogle, such as
+// "https://my-mirror.com/chromium-browser-snapshots". For air-gapped machines, the base can be a local dir,
+// such as "file:///srv/chromium-browser-snapshots", the zip should be placed like:
+//
+//     /srv/chromium-browser-snapshots/Linux_x64/856583/chrome-linux.zip
+//
+func HostMirror(base string) Host {
+	base = strings.TrimRight(base, "/")
+	return func(revision int) string {
+		return fmt.Sprintf("%s/%s/%d/%s", base, hostConf.urlPrefix, revision, hostConf.zipName)
+	}
+}
+
+// ErrChecksum is returned when the downloaded browser doesn't match the Manifest
+type ErrChecksum struct {
+	Revision int
+	Expected string
+	Actual   string
+}
+
+func (e *ErrChecksum) Error() string {
+	return fmt.Sprintf("sha256 checksum mismatch of chromium-%d, expected %s, got %s", e.Revision, e.Expected, e.Actual)
+}
+
+// ErrNotPinned is returned when the Manifest has no entry for the revision to download
+type ErrNotPinned struct {
+	Revision int
+}
+
+func (e *ErrNotPinned) Error() string {
+	return fmt.Sprintf("chromium-%d is not pinned in the manifest, use Browser.AllowUnpinned to trust the download",
+		e.Revision)
+}
+
+// Manifest of the pinned sha256 checksums of the browser zips. The key is the "{platform}/{revision}", such as
+// "Linux_x64/856583", the platform is the same as the one in the url of HostGoogle. The value is the hex encoded sha256
+// of the zip file. It's stored as a JSON object in a file.
+type Manifest map[string]string
+
+// LoadManifest from the JSON file
+func LoadManifest(path string) (Manifest, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := Manifest{}
+	return m, json.Unmarshal(b, &m)
+}
+
+// Save the manifest as a JSON file
+func (m Manifest) Save(path string) error {
+	return utils.OutputFile(path, m)
+}
+
+// Get the checksum of the revision for the current platform
+func (m Manifest) Get(revision int) (string, bool) {
+	sum, has := m[manifestKey(revision)]
+	return sum, has
+}
+
+// Set the checksum of the revision for the current platform
+func (m Manifest) Set(revision int, sum string) {
+	m[manifestKey(revision)] = strings.ToLower(sum)
+}
+
+func (m Manifest) clone() Manifest {
+	c := Manifest{}
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+func manifestKey(revision int) string {
+	return fmt.Sprintf("%s/%d", hostConf.urlPrefix, revision)
+}
+
+// verify the zip file with the manifest, if the manifest doesn't have the revision and it's not strict
+// the checksum will be added to it
+func (lc *Browser) verify(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return err
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+
+	_, _ = fmt.Fprintln(lc.Logger, "SHA-256:", sum)
+
+	if lc.Manifest == nil {
+		return nil
+	}
+
+	expected, has := lc.Manifest.Get(lc.Revision)
+	if !has {
+		if lc.strict() {
+			return &ErrNotPinned{Revision: lc.Revision}
+		}
+		lc.Manifest.Set(lc.Revision, sum)
+		return nil
+	}
+
+	if !strings.EqualFold(expected, sum) {
+		return &ErrChecksum{Revision: lc.Revision, Expected: expected, Actual: sum}
+	}
+	return nil
+}
+
+// strict is true if the revision that has no entry in the Manifest should be rejected.
+// The default Manifest created by NewBrowser isn't strict, because the DefaultManifest may not pin the revision.
+func (lc *Browser) strict() bool {
+	if lc.AllowUnpinned {
+		return false
+	}
+	return lc.defaultManifest == nil ||
+		reflect.ValueOf(lc.Manifest).Pointer() != reflect.ValueOf(lc.defaultManifest).Pointer()
+}
+
+// fileTransport serves the "file://" urls, it supports the "Range: bytes={offset}-" header
+type fileTransport struct{}
+
+func (fileTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	res := &http.Response{
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+		Request:    r,
+	}
+	status := func(code int) *http.Response {
+		res.StatusCode = code
+		res.Status = fmt.Sprintf("%d %s", code, http.StatusText(code))
+		return res
+	}
+
+	path := filepath.FromSlash(r.URL.Path)
+	if runtime.GOOS == "windows" {
+		// such as "file:///C:/dir/file.zip"
+		path = strings.TrimPrefix(path, `\`)
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return status(http.StatusNotFound), nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err == nil && info.IsDir() {
+		_ = f.Close()
+		return status(http.StatusNotFound), nil
+	}
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	var offset int64
+	if rng := r.Header.Get("Range"); strings.HasPrefix(rng, "bytes=") && strings.HasSuffix(rng, "-") {
+		offset, _ = strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"), 10, 64)
+	}
+
+	if offset > info.Size() {
+		_ = f.Close()
+		return status(http.StatusRequestedRangeNotSatisfiable), nil
+	}
+
+	if offset > 0 {
+		_, err = f.Seek(offset, io.SeekStart)
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		status(http.StatusPartialContent)
+	} else {
+		status(http.StatusOK)
+	}
+
+	res.Body = f
+	res.ContentLength = info.Size() - offset
+	res.Header.Set("Content-Length", strconv.FormatInt(res.ContentLength, 10))
+	return res, nil
+}
@@ -11,6 +11,11 @@ func killGroup(pid int) {
 	_ = syscall.Kill(-pid, syscall.SIGKILL)
 }
 
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
 func (l *Launcher) osSetupCmd(cmd *exec.Cmd) {
 	if _, has := l.Get(flagXVFB); has {
 		*cmd = *exec.Command("xvfb-run", cmd.Args...)
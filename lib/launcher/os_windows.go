@@ -2,10 +2,28 @@
 
 package launcher
 
-import "os/exec"
+import (
+	"os/exec"
+	"syscall"
+)
 
 func killGroup(pid int) {
 }
 
+func processAlive(pid int) bool {
+	const processQueryLimitedInformation = 0x1000
+	const stillActive = 259
+
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer func() { _ = syscall.CloseHandle(h) }()
+
+	var code uint32
+	err = syscall.GetExitCodeProcess(h, &code)
+	return err == nil && code == stillActive
+}
+
 func (l *Launcher) osSetupCmd(cmd *exec.Cmd) {
 }
@@ -2,11 +2,17 @@ package launcher
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"testing"
 	"time"
 
@@ -122,6 +128,165 @@ func (t T) RemoteLaunch() {
 	t.Err(os.Stat(dir))
 }
 
+func (t T) RemoteLauncherQueue() {
+	rl := NewRemoteLauncher()
+	rl.Max = 1
+	rl.WarmUp()
+
+	ctx := context.Background()
+	t.E(rl.acquire(ctx))
+
+	order := make(chan int, 2)
+	for i := 0; i < 2; i++ {
+		i := i
+		go func() {
+			t.E(rl.acquire(ctx))
+			order <- i
+		}()
+		for rl.Status().Queued != i+1 {
+			utils.Sleep(0.01)
+		}
+	}
+
+	rl.release()
+	t.Eq(0, <-order)
+	rl.release()
+	t.Eq(1, <-order)
+
+	rl.QueueTimeout = 10 * time.Millisecond
+	t.Eq(ErrQueueTimeout, rl.acquire(ctx))
+	t.Eq(int64(1), rl.Status().Timeouts)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	t.Eq(context.Canceled, rl.acquire(canceled))
+	t.Eq(0, rl.Status().Queued)
+
+	rl.release()
+	t.E(rl.acquire(ctx))
+	t.Eq(1, rl.running)
+}
+
+func (t T) RemoteLauncherWarmable() {
+	t.True(warmable(New()))
+	t.True(warmable(New().Set(flagLabel, "a=b")))
+	t.False(warmable(New().Set(flagKeepUserDataDir)))
+	t.False(warmable(New().Set("window-size", "10,10")))
+}
+
+func (t T) RemoteLauncherGrid() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	s := got.New(t).Serve()
+	rl := NewRemoteLauncher()
+	rl.Max = 1
+	rl.QueueTimeout = time.Second
+	rl.Warm = 1
+	defer rl.Close()
+	s.Mux.Handle("/", rl)
+
+	for rl.Status().Warm == 0 {
+		utils.Sleep(0.1)
+	}
+
+	l := MustNewRemote(s.URL()).Label("team", "a")
+	b := l.Client().MustConnect(ctx)
+	t.E(b.Call(ctx, "", "Browser.getVersion", nil))
+
+	status := rl.Status()
+	t.Len(status.Sessions, 1)
+	session := status.Sessions[0]
+	t.Eq("a", session.Labels["team"])
+	t.True(session.Warm)
+	t.Gt(session.PID, 0)
+	t.Has(t.Req("", s.URL("/status")).String(), session.ID)
+	t.Has(t.Req("", s.URL("/metrics")).String(), "rod_launcher_sessions 1")
+
+	// the max is reached
+	t.Err(MustNewRemote(s.URL()).Client().Connect(ctx))
+	t.Eq(int64(1), rl.Status().Timeouts)
+
+	t.Eq(404, t.Req("DELETE", s.URL("/sessions/not-exists")).StatusCode)
+	t.Eq(200, t.Req("DELETE", s.URL("/sessions/"+session.ID)).StatusCode)
+
+	for len(rl.Status().Sessions) != 0 {
+		utils.Sleep(0.1)
+	}
+}
+
+func (t T) RemoteLauncherAuth() {
+	rl := NewRemoteLauncher()
+	rl.Token = "secret"
+	s := httptest.NewTLSServer(rl)
+	defer s.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(s.Certificate())
+	conf := &tls.Config{RootCAs: pool}
+
+	_, err := NewRemote(s.URL, &RemoteOptions{TLS: conf})
+	t.Has(err.Error(), "401 Unauthorized")
+
+	_, err = NewRemote(s.URL, &RemoteOptions{Token: "wrong", TLS: conf})
+	t.Err(err)
+
+	_, err = NewRemote(s.URL, &RemoteOptions{Token: "secret"})
+	t.Err(err)
+
+	req, err := http.NewRequest(http.MethodGet, s.URL, nil)
+	t.E(err)
+	req.Header.Set("Authorization", "secret")
+	t.False(rl.authorized(req))
+	req.Header.Set("Authorization", "Bearer secret")
+	t.True(rl.authorized(req))
+
+	l := MustNewRemote(s.URL, &RemoteOptions{Token: "secret", TLS: conf})
+	t.Has(string(l.JSON()), "headless")
+	t.Eq("Bearer secret", l.remoteOpts.header().Get("Authorization"))
+}
+
+func (t T) RemoteLauncherAllowFlags() {
+	rl := NewRemoteLauncher()
+
+	t.E(rl.checkFlags(New()))
+	t.E(rl.checkFlags(New().Headless(false).Set("window-size", "10,10").Set(flagLabel, "a=b")))
+
+	l := New().Set("user-data-dir", "/")
+	t.E(rl.checkFlags(l))
+	dir, _ := l.Get("user-data-dir")
+	t.Neq(dir, "/")
+	t.Err(rl.checkFlags(New().Set(flagKeepUserDataDir)))
+
+	t.Eq(`flag is not allowed: "rod-env"`, rl.checkFlags(New().Env("a=b")).Error())
+	t.Err(rl.checkFlags(New().WorkingDir("/")))
+	t.Err(rl.checkFlags(New().StartURL("--renderer-cmd-prefix=sh")))
+	t.Err(rl.checkFlags(New().Delete("disable-sync")))
+
+	rl.AllowFlags = []string{"--rod-env"}
+	t.E(rl.checkFlags(New().Env("a=b")))
+	t.Err(rl.checkFlags(New().Headless(false)))
+
+	rl.AllowFlags = []string{"*"}
+	l = New().WorkingDir("/").Set("user-data-dir", "/")
+	t.E(rl.checkFlags(l))
+	dir, _ = l.Get("user-data-dir")
+	t.Eq(dir, "/")
+
+	s := got.New(t).Serve()
+	rl.AllowFlags = nil
+	s.Mux.Handle("/", rl)
+
+	req, err := http.NewRequest(http.MethodGet, s.URL(), nil)
+	t.E(err)
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set(HeaderName, string(New().Env("a=b").JSON()))
+	res, err := http.DefaultClient.Do(req)
+	t.E(err)
+	t.E(res.Body.Close())
+	t.Eq(403, res.StatusCode)
+}
+
 func (t T) LaunchErrs() {
 	l := New().Bin("echo")
 	_, err := l.Launch()
@@ -137,6 +302,31 @@ func (t T) LaunchErrs() {
 	t.Err(err)
 }
 
+func (t T) LaunchRegister() {
+	if runtime.GOOS == "windows" {
+		t.Skip("pipe mode is not supported on windows")
+	}
+
+	l := New()
+	l.browser.Dir = filepath.Join("tmp", "browser-register", t.Srand(16))
+	l.browser.Revision = 1
+	t.E(utils.OutputFile(l.browser.Destination(), "#!/bin/sh\nsleep 10\n"))
+	t.E(os.Chmod(l.browser.Destination(), 0755))
+
+	_, err := l.LaunchPipe()
+	t.E(err)
+
+	record := filepath.Join(l.browser.Dir, "chromium-1", usersDir, strconv.Itoa(l.PID()))
+	t.Nil(os.Stat(record))
+	list, err := l.browser.Revisions()
+	t.E(err)
+	t.True(list[0].InUse)
+
+	l.Kill()
+	<-l.Exit()
+	t.Err(os.Stat(record))
+}
+
 func (t T) Progresser() {
 	p := progresser{size: 100, logger: ioutil.Discard}
 
@@ -1,10 +1,16 @@
 package launcher
 
 import (
+	"crypto/tls"
 	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-rod/rod/lib/cdp"
 	"github.com/go-rod/rod/lib/utils"
@@ -15,16 +21,19 @@ const HeaderName = "Rod-Launcher"
 
 const flagKeepUserDataDir = "rod-keep-user-data-dir"
 
+const flagLabel = "rod-label"
+
 // MustNewRemote is similar to NewRemote
-func MustNewRemote(remoteURL string) *Launcher {
-	l, err := NewRemote(remoteURL)
+func MustNewRemote(remoteURL string, opts ...*RemoteOptions) *Launcher {
+	l, err := NewRemote(remoteURL, opts...)
 	utils.E(err)
 	return l
 }
 
 // NewRemote creates a Launcher instance from remote defaults.
+// Use the opts to authenticate to the remote launcher, the Launcher.Client will use the same opts.
 // For more info check the doc of RemoteLauncher.
-func NewRemote(remoteURL string) (*Launcher, error) {
+func NewRemote(remoteURL string, opts ...*RemoteOptions) (*Launcher, error) {
 	u, err := url.Parse(remoteURL)
 	if err != nil {
 		return nil, err
@@ -32,24 +41,47 @@ func NewRemote(remoteURL string) (*Launcher, error) {
 
 	l := New()
 	l.remote = true
+	l.remoteOpts = &RemoteOptions{}
+	if len(opts) > 0 && opts[0] != nil {
+		l.remoteOpts = opts[0]
+	}
 	l.url = toWS(*u).String()
 	l.Flags = nil
 
-	res, err := http.Get(toHTTP(*u).String())
+	req, err := http.NewRequest(http.MethodGet, toHTTP(*u).String(), nil)
 	if err != nil {
 		return nil, err
 	}
+	req.Header = l.remoteOpts.header()
+
+	res, err := l.remoteOpts.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(res.Body)
+		return nil, fmt.Errorf("remote launcher responded %s: %s", res.Status, strings.TrimSpace(string(body)))
+	}
 
 	return l, json.NewDecoder(res.Body).Decode(l)
 }
 
 // KeepUserDataDir after remote browser is closed. By default user-data-dir will be removed.
+// The RemoteLauncher must allow the "rod-keep-user-data-dir" flag via its AllowFlags.
 func (l *Launcher) KeepUserDataDir() *Launcher {
 	l.mustRemote()
 	l.Set(flagKeepUserDataDir)
 	return l
 }
 
+// Label the remote session, the labels are listed by the status endpoint of the RemoteLauncher
+func (l *Launcher) Label(key, value string) *Launcher {
+	l.mustRemote()
+	return l.Append(flagLabel, key+"="+value)
+}
+
 // JSON serialization
 func (l *Launcher) JSON() []byte {
 	return utils.MustToJSONBytes(l)
@@ -58,9 +90,13 @@ func (l *Launcher) JSON() []byte {
 // Client for launching browser remotely, such as browser from a docker container.
 func (l *Launcher) Client() *cdp.Client {
 	l.mustRemote()
-	header := http.Header{}
+	header := l.remoteOpts.header()
 	header.Add(HeaderName, utils.MustToJSON(l))
-	return cdp.New(l.url).Header(header)
+	client := cdp.New(l.url).Header(header)
+	if l.remoteOpts.TLS != nil && strings.HasPrefix(l.url, "wss:") {
+		client.Websocket(&cdp.WebSocket{Dialer: &tls.Dialer{Config: l.remoteOpts.TLS}})
+	}
+	return client
 }
 
 func (l *Launcher) mustRemote() {
@@ -81,8 +117,52 @@ var _ http.Handler = &RemoteLauncher{}
 // Any websocket request will start a new browser and the request will be proxied to the browser.
 // The websocket header "Rod-Launcher" holds the options to launch browser.
 // If the websocket is closed, the browser will be killed.
+//
+// To use it as a browser grid, set the Max, QueueTimeout, and Warm. The "/status" path returns the
+// RemoteStatus as JSON, the "/metrics" path returns the metrics in Prometheus text format,
+// a DELETE request to "/sessions/{id}" kills the session.
+//
+// By default anyone who can reach the server can use it, set the Token and serve it with TLS before
+// exposing it to a shared network. For mTLS, serve it via a http.Server whose TLSConfig.ClientAuth
+// is tls.RequireAndVerifyClientCert. The AllowFlags limits the launch options the clients can change.
 type RemoteLauncher struct {
 	Logger utils.Logger
+
+	// Token for the bearer authentication, if it's not empty all the requests must have the header
+	// "Authorization: Bearer {Token}", or they will get http status 401.
+	// Use the RemoteOptions.Token for the client side.
+	Token string
+
+	// AllowFlags is the allow-list of the flags that clients can set, change, or delete.
+	// The default flags of the server are always allowed as long as they are not changed.
+	// The request that uses other flags will get http status 403.
+	// Nil means the DefaultAllowFlags, use "*" to allow all flags, which is unsafe because flags
+	// like "user-data-dir", "rod-env", and "rod-working-dir" give the clients access to the server.
+	// If "user-data-dir" is not allowed, the browser always uses a fresh dir of the server.
+	AllowFlags []string
+
+	// Max number of the concurrent browsers, 0 means no limit.
+	// When it's reached, the new requests will wait in a FIFO queue.
+	Max int
+
+	// QueueTimeout is the max time a request waits in the queue, 0 means no timeout.
+	// The request will get http status 503 when it's timeout.
+	QueueTimeout time.Duration
+
+	// Warm is the number of the browsers pre-launched with the default options.
+	// The requests that don't customize the launch options will use them to skip the launch time.
+	// The idle warm browsers don't count for the Max.
+	Warm int
+
+	once     sync.Once
+	lock     sync.Mutex
+	running  int
+	queue    []chan struct{}
+	sessions map[string]*RemoteSession
+	warm     chan *warmBrowser
+	closed   bool
+	launched int64
+	timeouts int64
 }
 
 // NewRemoteLauncher instance
@@ -93,12 +173,28 @@ func NewRemoteLauncher() *RemoteLauncher {
 }
 
 func (p *RemoteLauncher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !p.authorized(r) {
+		p.Logger.Println("Unauthorized", r.RemoteAddr)
+		w.Header().Set("WWW-Authenticate", `Bearer realm="rod-launcher"`)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	if r.Header.Get("Upgrade") == "websocket" {
 		p.launch(w, r)
 		return
 	}
 
-	p.defaults(w, r)
+	switch {
+	case r.URL.Path == "/status":
+		p.status(w, r)
+	case r.URL.Path == "/metrics":
+		p.metrics(w, r)
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/sessions/"):
+		p.kill(w, r)
+	default:
+		p.defaults(w, r)
+	}
 }
 
 func (p *RemoteLauncher) defaults(w http.ResponseWriter, _ *http.Request) {
@@ -107,15 +203,44 @@ func (p *RemoteLauncher) defaults(w http.ResponseWriter, _ *http.Request) {
 }
 
 func (p *RemoteLauncher) launch(w http.ResponseWriter, r *http.Request) {
+	p.once.Do(p.setup)
+
 	l := New()
 
 	options := r.Header.Get(HeaderName)
 	if options != "" {
 		l.Flags = nil
 		utils.E(json.Unmarshal([]byte(options), l))
+
+		err := p.checkFlags(l)
+		if err != nil {
+			p.Logger.Println("Rejected", r.RemoteAddr, err)
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 	}
 
-	u := l.Leakless(false).MustLaunch()
+	err := p.acquire(r.Context())
+	if err != nil {
+		p.Logger.Println("Rejected", r.RemoteAddr, err)
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	defer p.release()
+
+	labels, _ := l.GetFlags(flagLabel)
+
+	var u string
+	wb := p.takeWarm(l)
+	if wb == nil {
+		u = l.Leakless(false).MustLaunch()
+	} else {
+		l, u = wb.l, wb.u
+	}
+
+	session := p.addSession(l, r.RemoteAddr, labels, wb != nil)
+	defer p.removeSession(session)
+
 	defer func() {
 		l.Kill()
 		p.Logger.Println("Killed PID:", l.PID())
@@ -1,12 +1,19 @@
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
 	"github.com/go-rod/rod/lib/launcher"
 	"github.com/go-rod/rod/lib/utils"
@@ -14,6 +21,16 @@ import (
 
 var addr = flag.String("address", ":9222", "the address to listen to")
 var quiet = flag.Bool("quiet", false, "silent the log")
+var maxBrowsers = flag.Int("max", 0, "max number of the concurrent browsers, 0 means no limit")
+var queueTimeout = flag.Duration("queue-timeout", 0, "max time a request waits for a free browser, 0 means no timeout")
+var warm = flag.Int("warm", 0, "number of the browsers to pre-launch with the default options")
+var token = flag.String("token", os.Getenv("ROD_LAUNCHER_TOKEN"), "the bearer token clients must send, "+
+	"defaults to the env ROD_LAUNCHER_TOKEN")
+var allowFlags = flag.String("allow-flags", "", "comma separated flags that clients can set, \"*\" means all, "+
+	"defaults to: "+strings.Join(launcher.DefaultAllowFlags, ","))
+var tlsCert = flag.String("tls-cert", "", "the certificate file to serve with TLS")
+var tlsKey = flag.String("tls-key", "", "the key file to serve with TLS")
+var clientCA = flag.String("client-ca", "", "the CA file to verify the client certificates, it enables the mTLS")
 
 // a cli tool to launch browser remotely
 func main() {
@@ -23,14 +40,63 @@ func main() {
 	if !*quiet {
 		rl.Logger = log.New(os.Stdout, "", 0)
 	}
+	rl.Max = *maxBrowsers
+	rl.QueueTimeout = *queueTimeout
+	rl.Warm = *warm
+	rl.Token = *token
+	if *allowFlags != "" {
+		rl.AllowFlags = strings.Split(*allowFlags, ",")
+	}
+	rl.WarmUp()
+
+	srv := &http.Server{Handler: rl}
+	scheme := "ws"
+	if *tlsCert != "" {
+		scheme = "wss"
+		srv.TLSConfig = tlsConfig()
+	} else if *clientCA != "" {
+		utils.E(errors.New("-client-ca requires -tls-cert and -tls-key"))
+	}
 
 	l, err := net.Listen("tcp", *addr)
 	if err != nil {
 		utils.E(err)
 	}
 
-	fmt.Println("Remote control url is", "ws://"+l.Addr().String())
+	fmt.Println("Remote control url is", scheme+"://"+l.Addr().String())
+	fmt.Println("Status url is", strings.Replace(scheme, "ws", "http", 1)+"://"+l.Addr().String()+"/status")
 
-	srv := &http.Server{Handler: rl}
-	utils.E(srv.Serve(l))
+	// kill the browsers before exit
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+		<-c
+		rl.Close()
+		os.Exit(0)
+	}()
+
+	if srv.TLSConfig != nil {
+		utils.E(srv.ServeTLS(l, *tlsCert, *tlsKey))
+	} else {
+		utils.E(srv.Serve(l))
+	}
+}
+
+func tlsConfig() *tls.Config {
+	conf := &tls.Config{MinVersion: tls.VersionTLS12}
+	if *clientCA == "" {
+		return conf
+	}
+
+	pem, err := ioutil.ReadFile(*clientCA)
+	utils.E(err)
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		utils.E(errors.New("no certificate found in " + *clientCA))
+	}
+
+	conf.ClientCAs = pool
+	conf.ClientAuth = tls.RequireAndVerifyClientCert
+	return conf
 }
@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/go-rod/rod/lib/utils"
@@ -15,16 +16,18 @@ import (
 var inContainer = utils.InContainer
 
 type progresser struct {
-	size   int
-	count  int
-	logger io.Writer
-	last   time.Time
+	size    int // negative means unknown
+	count   int
+	logger  io.Writer
+	last    time.Time
+	started bool
 }
 
 func (p *progresser) Write(b []byte) (n int, err error) {
 	n = len(b)
 
-	if p.count == 0 {
+	if !p.started {
+		p.started = true
 		_, _ = fmt.Fprint(p.logger, "Progress:")
 	}
 
@@ -40,7 +43,11 @@ func (p *progresser) Write(b []byte) (n int, err error) {
 	}
 
 	p.last = time.Now()
-	_, _ = fmt.Fprintf(p.logger, " %02d%%", p.count*100/p.size)
+	if p.size <= 0 {
+		_, _ = fmt.Fprintf(p.logger, " %.1fMB", float64(p.count)/(1<<20))
+	} else {
+		_, _ = fmt.Fprintf(p.logger, " %02d%%", p.count*100/p.size)
+	}
 
 	return
 }
@@ -91,6 +98,11 @@ func unzip(logger io.Writer, from, to string) (err error) {
 	for _, f := range zr.File {
 		p := filepath.Join(to, f.Name)
 
+		// prevent the zip slip, such as a file named "../../.bashrc"
+		if !strings.HasPrefix(p, filepath.Clean(to)+string(filepath.Separator)) {
+			utils.E(fmt.Errorf("illegal file path in zip: %s", f.Name))
+		}
+
 		if f.FileInfo().IsDir() {
 			err := os.Mkdir(p, f.Mode())
 			utils.E(err)
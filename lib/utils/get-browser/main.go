@@ -1,10 +1,118 @@
+// A cli tool to manage the browsers downloaded by the launcher.
+// Run "go run ./lib/utils/get-browser -h" for the usage.
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
 	"github.com/go-rod/rod/lib/launcher"
 	"github.com/go-rod/rod/lib/utils"
 )
 
+var revisions = flag.String("revision", strconv.Itoa(launcher.DefaultRevision), "comma separated revisions to get or keep")
+var dir = flag.String("dir", "", "the dir of the downloaded browsers, defaults to launcher.NewBrowser().Dir")
+var mirror = flag.String("mirror", "", "the mirror to download from, such as file:///srv/chromium-browser-snapshots")
+var manifest = flag.String("manifest", "", "the manifest file to verify the downloads, defaults to launcher.DefaultManifest")
+var pin = flag.Bool("pin", false, "trust the downloads that are not in the manifest, and add their checksums to the manifest file")
+var maxAge = flag.Duration("max-age", 0, "for gc, remove the revisions that haven't been used for longer than it")
+var maxCount = flag.Int("max-count", 0, "for gc, keep at most this number of the most recently used revisions")
+
 func main() {
-	utils.E(launcher.NewBrowser().Get())
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), `Usage: get-browser [flags] [command]
+
+Commands:
+  get                  download the revisions if they don't exist, it's the default command
+  list                 list the installed revisions with their size, last use time, and whether they are in use
+  gc                   remove the unused revisions by the -max-age and -max-count, the -revision ones and the
+                       ones in use are kept
+  prune                remove the installed revisions except the ones of the -revision flag
+  resolve <constraint> print the newest installed revision that matches the constraint, such as ">=850000"
+
+Flags:`)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	list := []int{}
+	for _, s := range strings.Split(*revisions, ",") {
+		r, err := strconv.Atoi(strings.TrimSpace(s))
+		utils.E(err)
+		list = append(list, r)
+	}
+
+	b := launcher.NewBrowser()
+	if *dir != "" {
+		b.Dir = *dir
+	}
+	if *mirror != "" {
+		b.Hosts = []launcher.Host{launcher.HostMirror(*mirror)}
+	}
+
+	switch flag.Arg(0) {
+	case "", "get":
+		get(b, list)
+	case "list":
+		revisions, err := b.Revisions()
+		utils.E(err)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "REVISION\tSIZE\tLAST USED\tIN USE\tPATH")
+		for _, r := range revisions {
+			fmt.Fprintf(w, "%d\t%.1fMB\t%s\t%v\t%s\n",
+				r.Revision, float64(r.Size)/(1<<20), r.LastUsed.Format(time.RFC3339), r.InUse, r.Path)
+		}
+		utils.E(w.Flush())
+	case "gc":
+		b.Revision = list[0]
+		removed, err := b.GC(launcher.GCOptions{MaxAge: *maxAge, MaxCount: *maxCount, Keep: list})
+		utils.E(err)
+		printRemoved(removed)
+	case "prune":
+		removed, err := b.Prune(list...)
+		utils.E(err)
+		printRemoved(removed)
+	case "resolve":
+		r, err := b.Resolve(flag.Arg(1))
+		utils.E(err)
+		fmt.Println(r.Revision, r.Path)
+	default:
+		flag.Usage()
+		os.Exit(1)
+	}
+}
+
+func get(b *launcher.Browser, list []int) {
+	if *manifest != "" {
+		m, err := launcher.LoadManifest(*manifest)
+		if os.IsNotExist(err) && *pin {
+			m, err = launcher.Manifest{}, nil
+		}
+		utils.E(err)
+		b.Manifest = m
+	}
+
+	b.AllowUnpinned = *pin
+
+	for _, r := range list {
+		b.Revision = r
+		p, err := b.Get()
+		utils.E(err)
+		fmt.Println("Browser:", p)
+	}
+
+	if *manifest != "" && *pin {
+		utils.E(b.Manifest.Save(*manifest))
+	}
+}
+
+func printRemoved(list []string) {
+	for _, p := range list {
+		fmt.Println("Removed:", p)
+	}
 }
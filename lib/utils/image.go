@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"image"
+	"image/color"
+)
+
+// ImageDiff is the result of DiffImage
+type ImageDiff struct {
+	// Image is a faded copy of the first image, the different pixels are red, the masked pixels are blue
+	Image *image.NRGBA
+
+	// Count of the different pixels
+	Count int
+
+	// Total of the compared pixels, the masked ones are excluded
+	Total int
+}
+
+// Ratio of the different pixels to the compared pixels
+func (d *ImageDiff) Ratio() float64 {
+	if d.Total == 0 {
+		return 0
+	}
+	return float64(d.Count) / float64(d.Total)
+}
+
+// the max value of the yiqDelta
+const maxYIQDelta = 35215.0
+
+// DiffImage compares two images pixel by pixel with the perceptual color difference in YIQ color space.
+// The threshold is from 0 to 1, the smaller the more sensitive, 0 means the pixels must be exactly the same.
+// The pixels inside the masks are ignored. If the sizes are different, the pixels that are not
+// in both of the images are treated as different.
+func DiffImage(a, b image.Image, threshold float64, masks ...image.Rectangle) *ImageDiff {
+	ra, rb := a.Bounds(), b.Bounds()
+	size := image.Rect(0, 0, max(ra.Dx(), rb.Dx()), max(ra.Dy(), rb.Dy()))
+
+	diff := &ImageDiff{Image: image.NewNRGBA(size)}
+	limit := maxYIQDelta * threshold * threshold
+
+	red := color.NRGBA{R: 255, A: 255}
+	blue := color.NRGBA{B: 255, A: 64}
+
+	for y := 0; y < size.Dy(); y++ {
+		for x := 0; x < size.Dx(); x++ {
+			p := image.Pt(x, y)
+
+			if inRects(p, masks) {
+				diff.Image.SetNRGBA(x, y, blue)
+				continue
+			}
+
+			diff.Total++
+
+			pa, pb := p.Add(ra.Min), p.Add(rb.Min)
+			if !pa.In(ra) || !pb.In(rb) {
+				diff.Count++
+				diff.Image.SetNRGBA(x, y, red)
+				continue
+			}
+
+			ca, cb := a.At(pa.X, pa.Y), b.At(pb.X, pb.Y)
+			if yiqDelta(ca, cb) > limit {
+				diff.Count++
+				diff.Image.SetNRGBA(x, y, red)
+				continue
+			}
+
+			// faded gray of the pixel, so that it's easy to locate the different pixels
+			gray := 255 - uint8(float64(255-grayOf(ca))*0.1)
+			diff.Image.SetNRGBA(x, y, color.NRGBA{gray, gray, gray, 255})
+		}
+	}
+
+	return diff
+}
+
+func inRects(p image.Point, rects []image.Rectangle) bool {
+	for _, r := range rects {
+		if p.In(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// blend the color with white background, returns the rgb from 0 to 255
+func blendWhite(c color.Color) (float64, float64, float64) {
+	r, g, b, a := c.RGBA()
+	if a == 0 {
+		return 255, 255, 255
+	}
+	alpha := float64(a) / 0xffff
+	// the rgb are alpha-premultiplied
+	f := func(v uint32) float64 { return float64(v)/0xffff*255 + 255*(1-alpha) }
+	return f(r), f(g), f(b)
+}
+
+func grayOf(c color.Color) uint8 {
+	r, g, b := blendWhite(c)
+	return uint8(0.299*r + 0.587*g + 0.114*b)
+}
+
+// the squared perceptual distance of two colors, from "Measuring perceived color difference
+// using YIQ NTSC transmission color space in mobile applications" by Y. Kotsarenko and F. Ramos
+func yiqDelta(a, b color.Color) float64 {
+	r1, g1, b1 := blendWhite(a)
+	r2, g2, b2 := blendWhite(b)
+
+	y := rgb2y(r1, g1, b1) - rgb2y(r2, g2, b2)
+	i := rgb2i(r1, g1, b1) - rgb2i(r2, g2, b2)
+	q := rgb2q(r1, g1, b1) - rgb2q(r2, g2, b2)
+
+	return 0.5053*y*y + 0.299*i*i + 0.1957*q*q
+}
+
+func rgb2y(r, g, b float64) float64 { return r*0.29889531 + g*0.58662247 + b*0.11448223 }
+func rgb2i(r, g, b float64) float64 { return r*0.59597799 - g*0.27417610 - b*0.32180189 }
+func rgb2q(r, g, b float64) float64 { return r*0.21147017 - g*0.52261711 + b*0.31114694 }
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
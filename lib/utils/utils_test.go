@@ -1,13 +1,19 @@
 package utils_test
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/hex"
 	"errors"
 	"image"
+	"image/color"
 	"image/jpeg"
 	"image/png"
+	"io"
+	"net"
+	"net/http"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -204,3 +210,107 @@ func (t T) CropImage() {
 	t.E(jpeg.Encode(bin, img, &jpeg.Options{Quality: 80}))
 	t.E(utils.CropImage(bin.Bytes(), 0, 10, 10, 30, 30))
 }
+
+func (t T) DiffImage() {
+	a := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	b := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	for i := range a.Pix {
+		a.Pix[i] = 255
+		b.Pix[i] = 255
+	}
+
+	d := utils.DiffImage(a, b, 0)
+	t.Eq(0, d.Count)
+	t.Eq(100, d.Total)
+	t.Eq(0.0, d.Ratio())
+
+	b.SetNRGBA(1, 1, color.NRGBA{0, 0, 0, 255})
+	b.SetNRGBA(2, 2, color.NRGBA{250, 250, 250, 255})
+
+	d = utils.DiffImage(a, b, 0)
+	t.Eq(2, d.Count)
+	t.Eq(color.NRGBA{255, 0, 0, 255}, d.Image.NRGBAAt(1, 1))
+
+	d = utils.DiffImage(a, b, 0.1)
+	t.Eq(1, d.Count)
+	t.Eq(0.01, d.Ratio())
+
+	d = utils.DiffImage(a, b, 0.1, image.Rect(0, 0, 2, 2))
+	t.Eq(0, d.Count)
+	t.Eq(96, d.Total)
+	t.Eq(color.NRGBA{0, 0, 255, 64}, d.Image.NRGBAAt(1, 1))
+
+	c := image.NewNRGBA(image.Rect(0, 0, 10, 12))
+	copy(c.Pix, a.Pix)
+	d = utils.DiffImage(a, c, 0.1)
+	t.Eq(120, d.Total)
+	t.Eq(20, d.Count)
+
+	t.Eq(0.0, (&utils.ImageDiff{}).Ratio())
+}
+
+func (t T) WebSocket() {
+	s := t.Serve()
+	s.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		ws, err := utils.UpgradeWebSocket(w, r)
+		if err != nil {
+			t.Eq(err, utils.ErrWebSocket)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer func() { _ = ws.Close() }()
+
+		msg, err := ws.Read()
+		t.E(err)
+		t.E(ws.WriteJSON(string(msg)))
+
+		_, err = ws.Read()
+		t.Eq(err, io.EOF)
+	})
+
+	res, err := http.Get(s.URL())
+	t.E(err)
+	t.Eq(res.StatusCode, http.StatusBadRequest)
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(s.URL(), "http://"))
+	t.E(err)
+	defer func() { _ = conn.Close() }()
+	_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: test\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n"))
+	t.E(err)
+
+	r := bufio.NewReader(conn)
+	res, err = http.ReadResponse(r, nil)
+	t.E(err)
+	t.Eq(res.StatusCode, http.StatusSwitchingProtocols)
+	t.Eq(res.Header.Get("Sec-WebSocket-Accept"), "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=")
+
+	// a masked ping, then a masked text message in two fragments
+	mask := []byte{1, 2, 3, 4}
+	frame := func(head byte, payload string) []byte {
+		data := append([]byte{head, 0x80 | byte(len(payload))}, mask...)
+		for i := range payload {
+			data = append(data, payload[i]^mask[i%4])
+		}
+		return data
+	}
+	_, err = conn.Write(bytes.Join([][]byte{frame(0x89, "p"), frame(0x01, "o"), frame(0x80, "k")}, nil))
+	t.E(err)
+
+	buf := make([]byte, 3)
+	_, err = io.ReadFull(r, buf)
+	t.E(err)
+	t.Eq(buf, []byte{0x8a, 1, 'p'})
+
+	buf = make([]byte, 6)
+	_, err = io.ReadFull(r, buf)
+	t.E(err)
+	t.Eq(buf, []byte{0x81, 4, '"', 'o', 'k', '"'})
+
+	_, err = conn.Write(frame(0x88, ""))
+	t.E(err)
+	buf = make([]byte, 2)
+	_, err = io.ReadFull(r, buf)
+	t.E(err)
+	t.Eq(buf, []byte{0x88, 0})
+}
@@ -0,0 +1,182 @@
+package utils
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// WebSocket is a minimal server side WebSocket connection, only text frames are sent.
+// Ref: https://tools.ietf.org/html/rfc6455
+type WebSocket struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+	lock sync.Mutex
+}
+
+// ErrWebSocket is returned when the request isn't a valid websocket request or the message is too large
+var ErrWebSocket = errors.New("invalid websocket request")
+
+// UpgradeWebSocket hijacks the http connection of the request and upgrades it to a WebSocket
+func UpgradeWebSocket(w http.ResponseWriter, r *http.Request) (*WebSocket, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || key == "" {
+		return nil, ErrWebSocket
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, ErrWebSocket
+	}
+
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha1.Sum([]byte(key + "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"))
+	_, err = fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\n"+
+		"Upgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n",
+		base64.StdEncoding.EncodeToString(hash[:]))
+	if err == nil {
+		err = rw.Flush()
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return &WebSocket{conn: conn, rw: rw}, nil
+}
+
+// max size of a message from the client
+const webSocketMaxMessage = 1 << 20
+
+// Read a whole message, the control frames are handled internally
+func (ws *WebSocket) Read() ([]byte, error) {
+	msg := []byte{}
+
+	for {
+		header := make([]byte, 2)
+		_, err := io.ReadFull(ws.rw, header)
+		if err != nil {
+			return nil, err
+		}
+
+		fin := header[0]&0x80 != 0
+		opcode := header[0] & 0x0f
+		masked := header[1]&0x80 != 0
+		size := uint64(header[1] & 0x7f)
+
+		switch size {
+		case 126:
+			ext := make([]byte, 2)
+			_, err = io.ReadFull(ws.rw, ext)
+			size = uint64(binary.BigEndian.Uint16(ext))
+		case 127:
+			ext := make([]byte, 8)
+			_, err = io.ReadFull(ws.rw, ext)
+			size = binary.BigEndian.Uint64(ext)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if size+uint64(len(msg)) > webSocketMaxMessage {
+			return nil, ErrWebSocket
+		}
+
+		mask := make([]byte, 4)
+		if masked {
+			_, err = io.ReadFull(ws.rw, mask)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		payload := make([]byte, size)
+		_, err = io.ReadFull(ws.rw, payload)
+		if err != nil {
+			return nil, err
+		}
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+
+		switch opcode {
+		case 0x8: // close
+			_ = ws.write(0x8, nil)
+			return nil, io.EOF
+		case 0x9: // ping
+			err = ws.write(0xa, payload)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		case 0xa: // pong
+			continue
+		}
+
+		msg = append(msg, payload...)
+		if fin {
+			return msg, nil
+		}
+	}
+}
+
+// Write the data as a text message
+func (ws *WebSocket) Write(data []byte) error {
+	return ws.write(0x1, data)
+}
+
+// WriteJSON sends the value as a text message
+func (ws *WebSocket) WriteJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return ws.Write(data)
+}
+
+func (ws *WebSocket) write(opcode byte, data []byte) error {
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
+
+	header := []byte{0x80 | opcode, 0}
+	size := len(data)
+	switch {
+	case size <= 125:
+		header[1] = byte(size)
+	case size < 65536:
+		header[1] = 126
+		header = append(header, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(size))
+	default:
+		header[1] = 127
+		header = append(header, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(size))
+	}
+
+	_, err := ws.rw.Write(header)
+	if err != nil {
+		return err
+	}
+	_, err = ws.rw.Write(data)
+	if err != nil {
+		return err
+	}
+	return ws.rw.Flush()
+}
+
+// Close the connection
+func (ws *WebSocket) Close() error {
+	return ws.conn.Close()
+}
@@ -0,0 +1,230 @@
+package rod
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/go-rod/rod/lib/input"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/go-rod/rod/lib/utils"
+)
+
+type operatorRequest struct {
+	Message string `json:"message"`
+	done    chan struct{}
+	once    sync.Once
+}
+
+func (r *operatorRequest) handBack() {
+	r.once.Do(func() { close(r.done) })
+}
+
+// WaitOperator blocks until an operator hands the page back via the live view of the Browser.ServeMonitor.
+// It's useful when the automation is stuck on a step that needs a human, such as a captcha or
+// a login with 2FA. The msg will be shown to the operator. Use the MonitorOptions.Control to let the operator
+// drive the page.
+func (p *Page) WaitOperator(msg string) error {
+	defer p.tryTrace(TraceTypeWait, "operator", msg)()
+
+	req := &operatorRequest{Message: msg, done: make(chan struct{})}
+	p.browser.operators.Store(p.TargetID, req)
+	defer p.browser.operators.Delete(p.TargetID)
+
+	select {
+	case <-p.ctx.Done():
+		return p.ctx.Err()
+	case <-req.done:
+		return nil
+	}
+}
+
+// sameOrigin rejects the requests from the pages of other origins, such as a malicious site that is opened
+// in the browser of the operator. The requests without the Origin header are not from a browser page.
+func sameOrigin(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if origin := r.Header.Get("Origin"); origin != "" {
+			u, err := url.Parse(origin)
+			if err != nil || u.Host != r.Host {
+				http.Error(w, "cross origin request is not allowed", http.StatusForbidden)
+				return
+			}
+		}
+		h(w, r)
+	}
+}
+
+// GET returns the operator request of the page, POST hands the page back to the script
+func (b *Browser) serveOperator(w http.ResponseWriter, r *http.Request) {
+	id := proto.TargetTargetID(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+
+	var req *operatorRequest
+	if v, has := b.operators.Load(id); has {
+		req = v.(*operatorRequest)
+	}
+
+	if r.Method == http.MethodPost && req != nil {
+		req.handBack()
+		req = nil
+	}
+
+	w.WriteHeader(http.StatusOK)
+	utils.E(w.Write(utils.MustToJSONBytes(map[string]interface{}{
+		"waiting": req != nil,
+		"request": req,
+	})))
+}
+
+// the input event from the live view of the monitor, the coordinates are in css pixels
+type monitorInput struct {
+	Type       string  `json:"type"` // mousemove, mousedown, mouseup, wheel, keydown, keyup, text
+	X          float64 `json:"x"`
+	Y          float64 `json:"y"`
+	Button     int     `json:"button"` // same as the MouseEvent.button of the browser
+	ClickCount int     `json:"clickCount"`
+	DeltaX     float64 `json:"deltaX"`
+	DeltaY     float64 `json:"deltaY"`
+	Key        string  `json:"key"` // same as the KeyboardEvent.key of the browser
+	Text       string  `json:"text"`
+}
+
+var monitorButtons = []proto.InputMouseButton{
+	proto.InputMouseButtonLeft,
+	proto.InputMouseButtonMiddle,
+	proto.InputMouseButtonRight,
+}
+
+func (e *monitorInput) dispatch(p *Page) error {
+	button := proto.InputMouseButtonLeft
+	if e.Button >= 0 && e.Button < len(monitorButtons) {
+		button = monitorButtons[e.Button]
+	}
+
+	switch e.Type {
+	case "mousemove":
+		return p.Mouse.Move(e.X, e.Y, 1)
+	case "mousedown":
+		err := p.Mouse.Move(e.X, e.Y, 1)
+		if err != nil {
+			return err
+		}
+		return p.Mouse.Down(button, e.ClickCount)
+	case "mouseup":
+		err := p.Mouse.Move(e.X, e.Y, 1)
+		if err != nil {
+			return err
+		}
+		return p.Mouse.Up(button, e.ClickCount)
+	case "wheel":
+		return p.Mouse.Scroll(e.DeltaX, e.DeltaY, 1)
+	case "keydown", "keyup":
+		key, ok := monitorKey(e.Key)
+		if !ok {
+			return nil
+		}
+		if e.Type == "keydown" {
+			return p.Keyboard.Down(key)
+		}
+		return p.Keyboard.Up(key)
+	case "text":
+		return p.Keyboard.InsertText(e.Text)
+	}
+	return fmt.Errorf("unknown input type: %s", e.Type)
+}
+
+var monitorKeys = func() map[string]rune {
+	dict := map[string]rune{}
+	for r, k := range input.Keys {
+		if _, has := dict[k.Key]; !has && k.Key != "" {
+			dict[k.Key] = r
+		}
+	}
+	return dict
+}()
+
+// converts the KeyboardEvent.key to the key of the Keyboard
+func monitorKey(key string) (rune, bool) {
+	if list := []rune(key); len(list) == 1 {
+		return list[0], true
+	}
+	r, has := monitorKeys[key]
+	return r, has
+}
+
+// errMonitorControl is returned when the input is received but the MonitorOptions.Control is disabled
+var errMonitorControl = errors.New("the control is disabled, enable it via the rod.MonitorOptions.Control")
+
+// streams the screencast frames of the page to the websocket, and dispatches the input events from it
+// if the control is enabled
+func (b *Browser) serveLiveView(control bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		b.liveView(w, r, control)
+	}
+}
+
+func (b *Browser) liveView(w http.ResponseWriter, r *http.Request, control bool) {
+	id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	p, err := b.PageFromTarget(proto.TargetTargetID(id))
+	utils.E(err)
+
+	conn, err := utils.UpgradeWebSocket(w, r)
+	utils.E(err)
+	defer func() { _ = conn.Close() }()
+
+	p, cancel := p.WithCancel()
+	defer cancel()
+	go func() {
+		<-p.ctx.Done()
+		_ = conn.Close()
+	}()
+
+	s, err := p.StartScreencast(&ScreencastOptions{Quality: 60})
+	if err != nil {
+		_ = conn.WriteJSON(map[string]string{"type": "error", "error": err.Error()})
+		return
+	}
+	defer func() { _ = s.Stop() }()
+
+	if conn.WriteJSON(map[string]interface{}{"type": "control", "enabled": control}) != nil {
+		return
+	}
+
+	go func() {
+		for frame := range s.Frames() {
+			err := conn.WriteJSON(map[string]interface{}{
+				"type":     "frame",
+				"data":     frame.Data,
+				"metadata": frame.Metadata,
+			})
+			if err != nil {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	for {
+		data, err := conn.Read()
+		if err != nil {
+			return
+		}
+
+		if !control {
+			_ = conn.WriteJSON(map[string]string{"type": "error", "error": errMonitorControl.Error()})
+			continue
+		}
+
+		e := &monitorInput{}
+		err = json.Unmarshal(data, e)
+		if err == nil {
+			err = e.dispatch(p)
+		}
+		if err != nil {
+			_ = conn.WriteJSON(map[string]string{"type": "error", "error": err.Error()})
+		}
+	}
+}
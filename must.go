@@ -10,6 +10,7 @@ package rod
 
 import (
 	"errors"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
@@ -69,6 +70,12 @@ func (b *Browser) MustHandleAuth(username, password string) (wait func()) {
 	return func() { utils.E(w()) }
 }
 
+// MustWaitEventMatch is similar to Browser.WaitEventMatch
+func (b *Browser) MustWaitEventMatch(filter EventFilter, timeout time.Duration, predicate interface{}) *Browser {
+	utils.E(b.WaitEventMatch(filter, timeout, predicate))
+	return b
+}
+
 // MustIgnoreCertErrors is similar to Browser.IgnoreCertErrors
 func (b *Browser) MustIgnoreCertErrors(enable bool) *Browser {
 	utils.E(b.IgnoreCertErrors(enable))
@@ -265,6 +272,12 @@ func (p *Page) MustEmulate(device devices.Device) *Page {
 	return p
 }
 
+// MustEmulateNetwork is similar to Page.EmulateNetwork
+func (p *Page) MustEmulateNetwork(network devices.Network) *Page {
+	utils.E(p.EmulateNetwork(network))
+	return p
+}
+
 // MustStopLoading is similar to Page.StopLoading
 func (p *Page) MustStopLoading() *Page {
 	utils.E(p.StopLoading())
@@ -296,6 +309,13 @@ func (p *Page) MustScreenshot(toFile ...string) []byte {
 	return bin
 }
 
+// MustMatchScreenshot is similar to Page.MatchScreenshot, but it panics with ErrScreenshotMismatch
+// if the screenshot doesn't match the baseline.
+func (p *Page) MustMatchScreenshot(name string, opts ...*VisualOptions) *Page {
+	utils.E(mustMatchScreenshot(p.MatchScreenshot(name, firstVisualOptions(opts))))
+	return p
+}
+
 // MustScreenshotFullPage is similar to ScreenshotFullPage.
 // If the toFile is "", it Page.will save output to "tmp/screenshots" folder, time as the file name.
 func (p *Page) MustScreenshotFullPage(toFile ...string) []byte {
@@ -305,6 +325,33 @@ func (p *Page) MustScreenshotFullPage(toFile ...string) []byte {
 	return bin
 }
 
+// MustScreenshotStitched is similar to ScreenshotStitched.
+// If the toFile is "", it Page.will save output to "tmp/screenshots" folder, time as the file name.
+func (p *Page) MustScreenshotStitched(opts *StitchOptions, toFile ...string) []byte {
+	bin, err := p.ScreenshotStitched(opts)
+	utils.E(err)
+	utils.E(saveFile(saveFileTypeScreenshot, bin, toFile))
+	return bin
+}
+
+// MustStartScreencast is similar to Page.StartScreencast
+func (p *Page) MustStartScreencast(opts ...*ScreencastOptions) *Screencast {
+	var o *ScreencastOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	s, err := p.StartScreencast(o)
+	utils.E(err)
+	return s
+}
+
+// MustRecordScreencast is similar to Page.RecordScreencast
+func (p *Page) MustRecordScreencast(opts *ScreencastOptions, w FrameWriter) (stop func()) {
+	s, err := p.RecordScreencast(opts, w)
+	utils.E(err)
+	return func() { utils.E(s()) }
+}
+
 // MustPDF is similar to PDF.
 // If the toFile is "", it Page.will save output to "tmp/pdf" folder, time as the file name.
 func (p *Page) MustPDF(toFile ...string) []byte {
@@ -337,6 +384,18 @@ func (p *Page) MustWaitRequestIdle(excludes ...string) (wait func()) {
 	return p.WaitRequestIdle(300*time.Millisecond, nil, excludes)
 }
 
+// MustRecordHAR is similar to Page.RecordHAR
+func (p *Page) MustRecordHAR(w io.Writer) (stop func()) {
+	s := p.RecordHAR(w)
+	return func() { utils.E(s()) }
+}
+
+// MustWaitEventMatch is similar to Page.WaitEventMatch
+func (p *Page) MustWaitEventMatch(filter EventFilter, timeout time.Duration, predicate interface{}) *Page {
+	utils.E(p.WaitEventMatch(filter, timeout, predicate))
+	return p
+}
+
 // MustWaitIdle is similar to Page.WaitIdle
 func (p *Page) MustWaitIdle() *Page {
 	utils.E(p.WaitIdle(time.Minute))
@@ -349,6 +408,12 @@ func (p *Page) MustWaitLoad() *Page {
 	return p
 }
 
+// MustWaitOperator is similar to Page.WaitOperator
+func (p *Page) MustWaitOperator(msg string) *Page {
+	utils.E(p.WaitOperator(msg))
+	return p
+}
+
 // MustAddScriptTag is similar to Page.AddScriptTag
 func (p *Page) MustAddScriptTag(url string) *Page {
 	utils.E(p.AddScriptTag(url, ""))
@@ -489,6 +554,34 @@ func (p *Page) MustElementX(xPath string) *Element {
 	return el
 }
 
+// MustElementL is similar to Page.ElementL
+func (p *Page) MustElementL(selector string) *Element {
+	el, err := p.ElementL(selector)
+	utils.E(err)
+	return el
+}
+
+// MustElementByRole is similar to Page.ElementByRole
+func (p *Page) MustElementByRole(role, name string) *Element {
+	el, err := p.ElementByRole(role, name)
+	utils.E(err)
+	return el
+}
+
+// MustAuditAccessibility is similar to Page.AuditAccessibility
+func (p *Page) MustAuditAccessibility() *AuditReport {
+	report, err := p.AuditAccessibility()
+	utils.E(err)
+	return report
+}
+
+// MustAXTree is similar to Page.AXTree
+func (p *Page) MustAXTree() *AXNode {
+	node, err := p.AXTree()
+	utils.E(err)
+	return node
+}
+
 // MustElementByJS is similar to Page.ElementByJS
 func (p *Page) MustElementByJS(js string, params ...interface{}) *Element {
 	el, err := p.ElementByJS(Eval(js, params...))
@@ -503,6 +596,13 @@ func (p *Page) MustElements(selector string) Elements {
 	return list
 }
 
+// MustElementsL is similar to Page.ElementsL
+func (p *Page) MustElementsL(selector string) Elements {
+	list, err := p.ElementsL(selector)
+	utils.E(err)
+	return list
+}
+
 // MustElementsX is similar to Page.ElementsX
 func (p *Page) MustElementsX(xpath string) Elements {
 	list, err := p.ElementsX(xpath)
@@ -864,6 +964,13 @@ func (el *Element) MustScreenshot(toFile ...string) []byte {
 	return bin
 }
 
+// MustMatchScreenshot is similar to Element.MatchScreenshot, but it panics with ErrScreenshotMismatch
+// if the screenshot doesn't match the baseline.
+func (el *Element) MustMatchScreenshot(name string, opts ...*VisualOptions) *Element {
+	utils.E(mustMatchScreenshot(el.MatchScreenshot(name, firstVisualOptions(opts))))
+	return el
+}
+
 // MustRelease is similar to Element.Release
 func (el *Element) MustRelease() {
 	utils.E(el.Release())
@@ -916,6 +1023,20 @@ func (el *Element) MustElementX(xpath string) *Element {
 	return el
 }
 
+// MustElementL is similar to Element.ElementL
+func (el *Element) MustElementL(selector string) *Element {
+	el, err := el.ElementL(selector)
+	utils.E(err)
+	return el
+}
+
+// MustElementByRole is similar to Element.ElementByRole
+func (el *Element) MustElementByRole(role, name string) *Element {
+	el, err := el.ElementByRole(role, name)
+	utils.E(err)
+	return el
+}
+
 // MustElementByJS is similar to Element.ElementByJS
 func (el *Element) MustElementByJS(js string, params ...interface{}) *Element {
 	el, err := el.ElementByJS(Eval(js, params...))
@@ -965,6 +1086,13 @@ func (el *Element) MustElements(selector string) Elements {
 	return list
 }
 
+// MustElementsL is similar to Element.ElementsL
+func (el *Element) MustElementsL(selector string) Elements {
+	list, err := el.ElementsL(selector)
+	utils.E(err)
+	return list
+}
+
 // MustElementsX is similar to Element.ElementsX
 func (el *Element) MustElementsX(xpath string) Elements {
 	list, err := el.ElementsX(xpath)
@@ -979,18 +1107,62 @@ func (el *Element) MustElementsByJS(js string, params ...interface{}) Elements {
 	return list
 }
 
+// MustWaitFor is similar to RequestRecorder.WaitFor
+func (r *RequestRecorder) MustWaitFor(m *RequestMatcher) *RecordedRequest {
+	req, err := r.WaitFor(m)
+	utils.E(err)
+	return req
+}
+
+// MustWaitFrame is similar to WebSockets.WaitFrame
+func (s *WebSockets) MustWaitFrame(match func(*WebSocketFrame) bool) *WebSocketFrame {
+	f, err := s.WaitFrame(match)
+	utils.E(err)
+	return f
+}
+
+// MustInject is similar to WebSocket.Inject
+func (ws *WebSocket) MustInject(data string) *WebSocket {
+	utils.E(ws.Inject(data))
+	return ws
+}
+
 // MustAdd is similar to HijackRouter.Add
 func (r *HijackRouter) MustAdd(pattern string, handler func(*Hijack)) *HijackRouter {
 	utils.E(r.Add(pattern, "", handler))
 	return r
 }
 
+// MustAddResponse is similar to HijackRouter.AddResponse
+func (r *HijackRouter) MustAddResponse(pattern string, handler func(*Hijack)) *HijackRouter {
+	utils.E(r.AddResponse(pattern, "", handler))
+	return r
+}
+
+// MustAddRoute is similar to HijackRouter.AddRoute
+func (r *HijackRouter) MustAddRoute(route *HijackRoute, handler func(*Hijack)) *HijackRouter {
+	utils.E(r.AddRoute(route, handler))
+	return r
+}
+
+// MustRemoveRoute is similar to HijackRouter.RemoveRoute
+func (r *HijackRouter) MustRemoveRoute(route *HijackRoute) *HijackRouter {
+	utils.E(r.RemoveRoute(route))
+	return r
+}
+
 // MustRemove is similar to HijackRouter.Remove
 func (r *HijackRouter) MustRemove(pattern string) *HijackRouter {
 	utils.E(r.Remove(pattern))
 	return r
 }
 
+// MustReplayHAR is similar to HijackRouter.ReplayHAR
+func (r *HijackRouter) MustReplayHAR(path string) *HijackRouter {
+	utils.E(r.ReplayHAR(path))
+	return r
+}
+
 // MustStop is similar to HijackRouter.Stop
 func (r *HijackRouter) MustStop() {
 	utils.E(r.Stop())
@@ -1007,3 +1179,27 @@ func (el *Element) MustEqual(elm *Element) bool {
 	utils.E(err)
 	return res
 }
+
+// MustElement is similar to AXNode.Element
+func (n *AXNode) MustElement() *Element {
+	el, err := n.Element()
+	utils.E(err)
+	return el
+}
+
+// MustElement is similar to AuditFinding.Element
+func (f *AuditFinding) MustElement() *Element {
+	el, err := f.Element()
+	utils.E(err)
+	return el
+}
+
+// MustStop is similar to Screencast.Stop
+func (s *Screencast) MustStop() {
+	utils.E(s.Stop())
+}
+
+// MustRecord is similar to Screencast.Record
+func (s *Screencast) MustRecord(w FrameWriter) {
+	utils.E(s.Record(w))
+}
@@ -144,6 +144,10 @@ func (p *Page) Navigate(url string) error {
 		url = "about:blank"
 	}
 
+	defer p.traceAction(func() *TraceEvent {
+		return &TraceEvent{Type: TraceTypeNavigate, Message: url}
+	})()
+
 	err := p.StopLoading()
 	if err != nil {
 		return err
@@ -258,7 +262,59 @@ func (p *Page) Emulate(device devices.Device) error {
 		return err
 	}
 
-	return p.SetUserAgent(device.UserAgentEmulation())
+	err = p.SetUserAgent(device.UserAgentEmulation())
+	if err != nil {
+		return err
+	}
+
+	if device.IsClear() {
+		return p.clearNetworkEmulation()
+	}
+
+	if device.Network != nil {
+		return p.EmulateNetwork(*device.Network)
+	}
+
+	return nil
+}
+
+// EmulateNetwork conditions, such as devices.NetworkSlow3G or a custom devices.Network.
+// Use devices.NetworkNoThrottle or Page.Emulate with devices.Clear to stop the emulation.
+func (p *Page) EmulateNetwork(network devices.Network) error {
+	req := network.NetworkEmulation()
+
+	if network.PacketLoss == 0 {
+		return req.Call(p)
+	}
+
+	// the packetLoss is newer than the protocol version of the proto package
+	_, err := p.Call(p.ctx, string(p.SessionID), req.ProtoReq(), &struct {
+		*proto.NetworkEmulateNetworkConditions
+		PacketLoss float64 `json:"packetLoss"`
+	}{req, network.PacketLoss})
+	if err != nil {
+		return err
+	}
+
+	// so that the state can be loaded via Page.LoadState
+	p.browser.set(p.SessionID, req.ProtoReq(), req)
+	return nil
+}
+
+// clearNetworkEmulation if the page is emulating a network
+func (p *Page) clearNetworkEmulation() error {
+	req := devices.NetworkNoThrottle.NetworkEmulation()
+	if !p.LoadState(&proto.NetworkEmulateNetworkConditions{}) {
+		return nil
+	}
+
+	err := req.Call(p)
+	if err != nil {
+		return err
+	}
+
+	p.browser.RemoveState(p.browser.key(p.SessionID, req.ProtoReq()))
+	return nil
 }
 
 // StopLoading forces the page stop navigation and pending resource fetches.
@@ -335,35 +391,17 @@ func (p *Page) HandleDialog() (
 }
 
 // Screenshot options: https://chromedevtools.github.io/devtools-protocol/tot/Page#method-captureScreenshot
+// For the pages that are taller than the texture limit of the browser, use Page.ScreenshotStitched instead.
 func (p *Page) Screenshot(fullpage bool, req *proto.PageCaptureScreenshot) ([]byte, error) {
 	if req == nil {
 		req = &proto.PageCaptureScreenshot{}
 	}
 	if fullpage {
-		metrics, err := proto.PageGetLayoutMetrics{}.Call(p)
-		if err != nil {
-			return nil, err
-		}
-
-		oldView := proto.EmulationSetDeviceMetricsOverride{}
-		set := p.LoadState(&oldView)
-		view := oldView
-		view.Width = int(metrics.ContentSize.Width)
-		view.Height = int(metrics.ContentSize.Height)
-
-		err = p.SetViewport(&view)
+		restore, err := p.fullPageViewport()
 		if err != nil {
 			return nil, err
 		}
-
-		defer func() { // try to recover the viewport
-			if !set {
-				_ = proto.EmulationClearDeviceMetricsOverride{}.Call(p)
-				return
-			}
-
-			_ = p.SetViewport(&oldView)
-		}()
+		defer restore()
 	}
 
 	shot, err := req.Call(p)
@@ -373,6 +411,34 @@ func (p *Page) Screenshot(fullpage bool, req *proto.PageCaptureScreenshot) ([]by
 	return shot.Data, nil
 }
 
+// resize the viewport to the content size of the page, the restore function tries to recover the viewport
+func (p *Page) fullPageViewport() (restore func(), err error) {
+	metrics, err := proto.PageGetLayoutMetrics{}.Call(p)
+	if err != nil {
+		return nil, err
+	}
+
+	oldView := proto.EmulationSetDeviceMetricsOverride{}
+	set := p.LoadState(&oldView)
+	view := oldView
+	view.Width = int(metrics.ContentSize.Width)
+	view.Height = int(metrics.ContentSize.Height)
+
+	err = p.SetViewport(&view)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		if !set {
+			_ = proto.EmulationClearDeviceMetricsOverride{}.Call(p)
+			return
+		}
+
+		_ = p.SetViewport(&oldView)
+	}, nil
+}
+
 // PDF prints page as PDF
 func (p *Page) PDF(req *proto.PagePrintToPDF) (*StreamReader, error) {
 	req.TransferMode = proto.PagePrintToPDFTransferModeReturnAsStream
@@ -430,6 +496,9 @@ func (p *Page) WaitOpen() func() (*Page, error) {
 //
 //     func(proto.Event, proto.TargetSessionID?) bool?
 //
+// The proto.TargetSessionID is the session of the event, for Browser.EachEvent it's the session of the page
+// that emits the event, or empty for the browser level events.
+//
 // You can listen to multiple event types at the same time like:
 //
 //     browser.EachEvent(func(a *proto.A) {}, func(b *proto.B) {})
@@ -445,6 +514,14 @@ func (p *Page) EachEvent(callbacks ...interface{}) (wait func()) {
 	return p.browser.Context(p.ctx).eachEvent(p.SessionID, callbacks...)
 }
 
+// EventBuffer returns a clone with the buffer settings for the event subscribers created by it,
+// such as Page.Event, Page.EachEvent, and Page.WaitEvent. Check Browser.EventBuffer for more details.
+func (p *Page) EventBuffer(size int, overflow EventOverflow) *Page {
+	newObj := *p
+	newObj.browser = p.browser.EventBuffer(size, overflow)
+	return &newObj
+}
+
 // WaitEvent waits for the next event for one time. It will also load the data into the event object.
 func (p *Page) WaitEvent(e proto.Event) (wait func()) {
 	defer p.tryTrace(TraceTypeWait, "event", e.ProtoEvent())()
@@ -508,6 +585,13 @@ func (p *Page) WaitRequestIdle(d time.Duration, includes, excludes []string) fun
 	})
 
 	return func() {
+		defer p.traceAction(func() *TraceEvent {
+			return &TraceEvent{
+				Type:    TraceTypeWaitRequestsIdle,
+				Message: utils.MustToJSON(map[string][]string{"includes": includes, "excludes": excludes}),
+			}
+		})()
+
 		go func() {
 			idleCounter.Wait(p.ctx)
 			cancel()
@@ -685,6 +769,11 @@ func (p *Page) Release(obj *proto.RuntimeRemoteObject) error {
 
 // Call implements the proto.Client
 func (p *Page) Call(ctx context.Context, sessionID, methodName string, params interface{}) (res []byte, err error) {
+	if p.browser.watchdog != nil {
+		return p.browser.watchdog.call(ctx, p.TargetID, methodName, func(ctx context.Context) ([]byte, error) {
+			return p.browser.call(ctx, sessionID, methodName, params)
+		})
+	}
 	return p.browser.Call(ctx, sessionID, methodName, params)
 }
 
@@ -3,11 +3,13 @@ package rod_test
 import (
 	"bytes"
 	"context"
+	"image/jpeg"
 	"image/png"
 	"net/http"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -230,6 +232,41 @@ func (t T) EmulateDevice() {
 	})
 }
 
+func (t T) EmulateNetwork() {
+	page := t.newPage(t.blank())
+	online := func() bool { return page.MustEval(`navigator.onLine`).Bool() }
+
+	page.MustEmulateNetwork(devices.NetworkOffline)
+	t.False(online())
+
+	conditions := proto.NetworkEmulateNetworkConditions{}
+	t.True(page.LoadState(&conditions))
+	t.True(conditions.Offline)
+
+	page.MustEmulateNetwork(devices.NetworkNoThrottle)
+	t.True(online())
+
+	page.MustEmulateNetwork(devices.Network{Latency: time.Millisecond, PacketLoss: 1})
+	t.True(page.LoadState(&conditions))
+	t.Eq(1, conditions.Latency)
+
+	page.MustEmulate(devices.IPad.WithNetwork(devices.NetworkOffline))
+	t.False(online())
+
+	page.MustEmulate(devices.Clear)
+	t.True(online())
+	t.False(page.LoadState(&conditions))
+
+	t.Panic(func() {
+		t.mc.stubErr(1, proto.NetworkEmulateNetworkConditions{})
+		page.MustEmulateNetwork(devices.NetworkSlow3G)
+	})
+	t.Panic(func() {
+		t.mc.stubErr(1, proto.NetworkEmulateNetworkConditions{})
+		page.MustEmulateNetwork(devices.Network{PacketLoss: 1})
+	})
+}
+
 func (t T) PageCloseErr() {
 	page := t.newPage(t.blank())
 	t.Panic(func() {
@@ -395,6 +432,42 @@ func (t T) PageWaitEventParseEventOnlyOnce() {
 	nav2()
 }
 
+func (t T) PageWaitEventMatch() {
+	s := t.Serve()
+	s.Route("/a", ".txt", "a")
+	s.Route("/b", ".txt", "b")
+	t.page.MustNavigate(s.URL())
+
+	go t.page.MustEval(`u => fetch(u + '/a').then(() => fetch(u + '/b'))`, s.URL())
+
+	var url string
+	t.page.MustWaitEventMatch(rod.EventFilter{}, time.Minute, func(e *proto.NetworkResponseReceived) bool {
+		url = e.Response.URL
+		return strings.HasSuffix(url, "/b")
+	})
+	t.Eq(s.URL("/b"), url)
+
+	err := t.page.WaitEventMatch(rod.EventFilter{}, time.Millisecond, func(e *proto.PageFrameNavigated) bool {
+		return true
+	})
+	t.Is(err, context.DeadlineExceeded)
+}
+
+func (t T) PageSubscribe() {
+	p := t.newPage(t.blank())
+
+	navigated := make(chan proto.PageFrameID, 10)
+	sub := p.Subscribe(rod.EventFilter{FrameID: p.FrameID}, func(e *proto.PageFrameNavigated) {
+		navigated <- e.Frame.ID
+	})
+
+	p.MustNavigate(t.blank())
+	t.Eq(p.FrameID, <-navigated)
+
+	sub.Unsubscribe()
+	t.False(sub.Stopped())
+}
+
 func (t T) PageEvent() {
 	p := t.browser.MustPage()
 	ctx := t.Context()
@@ -760,3 +833,50 @@ func (t T) PageElementFromObjectErr() {
 	t.mc.stubErr(1, proto.RuntimeEvaluate{})
 	t.Err(p.ElementFromObject(obj.Object))
 }
+
+func (t T) ScreenshotStitched() {
+	p := t.newPage(t.srcFile("fixtures/stitch.html"))
+	p.MustWaitLoad()
+
+	data := p.MustScreenshotStitched(&rod.StitchOptions{HideFixed: true})
+	img, err := png.Decode(bytes.NewBuffer(data))
+	t.E(err)
+
+	// taller than the texture limit, the lazy content is included
+	t.Eq(1280, img.Bounds().Dx())
+	t.Eq(19000, img.Bounds().Dy())
+
+	isColor := func(y int, r, g, b uint32) bool {
+		cr, cg, cb, _ := img.At(10, y).RGBA()
+		return cr>>8 == r && cg>>8 == g && cb>>8 == b
+	}
+	t.True(isColor(10, 255, 0, 0))
+	t.True(isColor(900, 0, 255, 0))  // the header is hidden in the second tile
+	t.True(isColor(6010, 0, 0, 255)) // the second band
+	t.True(isColor(18990, 255, 255, 0))
+
+	// the fixed header and the scroll position should be restored
+	t.Eq("visible", p.MustEval(`() => getComputedStyle(document.querySelector('header')).visibility`).Str())
+	t.Eq(0, p.MustEval(`() => window.scrollY`).Int())
+
+	data = p.MustScreenshotStitched(&rod.StitchOptions{
+		Format:    proto.PageCaptureScreenshotFormatJpeg,
+		MaxHeight: 1000,
+	})
+	img, err = jpeg.Decode(bytes.NewBuffer(data))
+	t.E(err)
+	t.Eq(1000, img.Bounds().Dy())
+
+	_, err = p.ScreenshotStitched(&rod.StitchOptions{MaxBytes: 1280 * 1000 * 4})
+	t.Is(err, &rod.ErrStitchTooLarge{})
+	t.Has(err.Error(), "1280x19000")
+
+	t.Panic(func() {
+		t.mc.stubErr(1, proto.PageCaptureScreenshot{})
+		p.MustScreenshotStitched(nil)
+	})
+	t.Panic(func() {
+		t.mc.stubErr(1, proto.PageGetLayoutMetrics{})
+		p.MustScreenshotStitched(nil)
+	})
+}
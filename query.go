@@ -155,11 +155,36 @@ func (p *Page) ElementX(xPath string) (*Element, error) {
 	return p.ElementByJS(evalHelper(js.ElementX, xPath))
 }
 
+// ElementL retries until an element in the page that matches the locator selector, then returns
+// the matched element. The selector is a list of steps separated by ">>", each step searches inside
+// the results of the previous step, such as:
+//
+//     page.ElementL(`form >> role=button[name="Save"]`)
+//
+// The syntax of a step is "engine=value", the available engines are:
+//
+//     css=form            the css selector, default engine if the step has no "engine=" prefix
+//     xpath=//form        the XPath selector, default engine if the step starts with "//"
+//     text=Save           the innermost elements whose text content matches
+//     role=button[name="Save"]  the elements with the aria role, filtered by the accessible name, level, etc
+//     label=Email         the form controls whose label matches
+//     placeholder=Email   the elements whose placeholder matches
+//     testid=save         the elements whose "data-testid" attribute equals to it
+//
+// For the text, role name, label, and placeholder, a value wrapped with quotes matches the whole text,
+// a value wrapped with "/" is a js regex, otherwise it matches the text that contains it case-insensitively.
+// Except the xpath, the engines pierce the open shadow roots and same-origin iframes.
+func (p *Page) ElementL(selector string) (*Element, error) {
+	return p.ElementByJS(evalHelper(js.ElementL, selector))
+}
+
 // ElementByJS returns the element from the return value of the js function.
 // If sleeper is nil, no retry will be performed.
 // By default, it will retry until the js function doesn't return null.
 // To customize the retry logic, check the examples of Page.Sleeper.
 func (p *Page) ElementByJS(opts *EvalOptions) (*Element, error) {
+	defer p.traceQuery("element", opts)()
+
 	var res *proto.RuntimeRemoteObject
 	var err error
 
@@ -202,8 +227,15 @@ func (p *Page) ElementsX(xpath string) (Elements, error) {
 	return p.ElementsByJS(evalHelper(js.ElementsX, xpath))
 }
 
+// ElementsL returns all elements that match the locator selector, check Page.ElementL for the syntax
+func (p *Page) ElementsL(selector string) (Elements, error) {
+	return p.ElementsByJS(evalHelper(js.ElementsL, selector))
+}
+
 // ElementsByJS returns the elements from the return value of the js
 func (p *Page) ElementsByJS(opts *EvalOptions) (Elements, error) {
+	defer p.traceQuery("elements", opts)()
+
 	res, err := p.Evaluate(opts.ByObject())
 	if err != nil {
 		return nil, err
@@ -402,6 +434,14 @@ func (rc *RaceContext) ElementR(selector, regex string) *RaceContext {
 	return rc
 }
 
+// ElementL the doc is similar to ElementL
+func (rc *RaceContext) ElementL(selector string) *RaceContext {
+	rc.branches = append(rc.branches, &raceBranch{
+		condition: func(p *Page) (*Element, error) { return p.ElementL(selector) },
+	})
+	return rc
+}
+
 // ElementByJS the doc is similar to MustElementByJS
 func (rc *RaceContext) ElementByJS(opts *EvalOptions) *RaceContext {
 	rc.branches = append(rc.branches, &raceBranch{
@@ -482,6 +522,11 @@ func (el *Element) ElementX(xPath string) (*Element, error) {
 	return el.ElementByJS(evalHelper(js.ElementX, xPath))
 }
 
+// ElementL returns the first child that matches the locator selector, check Page.ElementL for the syntax
+func (el *Element) ElementL(selector string) (*Element, error) {
+	return el.ElementByJS(evalHelper(js.ElementL, selector))
+}
+
 // ElementByJS returns the element from the return value of the js
 func (el *Element) ElementByJS(opts *EvalOptions) (*Element, error) {
 	e, err := el.page.Sleeper(NotFoundSleeper).ElementByJS(opts.This(el.Object))
@@ -521,6 +566,11 @@ func (el *Element) ElementsX(xpath string) (Elements, error) {
 	return el.ElementsByJS(evalHelper(js.ElementsX, xpath))
 }
 
+// ElementsL returns all elements that match the locator selector, check Page.ElementL for the syntax
+func (el *Element) ElementsL(selector string) (Elements, error) {
+	return el.ElementsByJS(evalHelper(js.ElementsL, selector))
+}
+
 // ElementsByJS returns the elements from the return value of the js
 func (el *Element) ElementsByJS(opts *EvalOptions) (Elements, error) {
 	return el.page.Context(el.ctx).ElementsByJS(opts.This(el.Object))
@@ -208,6 +208,28 @@ func (t T) PageElementsX() {
 	t.Len(list, 4)
 }
 
+func (t T) ElementL() {
+	p := t.page.MustNavigate(t.srcFile("fixtures/locator.html"))
+	p.MustWaitLoad()
+
+	t.Eq("SPAN", p.MustElementL("text=save").MustEval(`() => this.tagName`).Str())
+	t.Eq("save", *p.MustElementL(`form >> role=button[name="Save"]`).MustAttribute("data-testid"))
+	t.Len(p.MustElementsL(`role=button[name="Save"]`), 2)
+	t.Eq("email", *p.MustElementL("label=email").MustAttribute("id"))
+	t.Eq("name", *p.MustElementL(`label="Name"`).MustAttribute("id"))
+	t.Eq("email", *p.MustElementL("placeholder=/@example/").MustAttribute("id"))
+	t.Eq("Save", p.MustElementL("testid=save").MustElementL("css=span").MustText())
+	t.Eq("In Shadow", p.MustElementL("#host >> role=button").MustText())
+	t.Eq("In Frame", p.MustElementL(`text="In Frame"`).MustText())
+	t.Eq("H1", p.MustElementL("//h1").MustEval(`() => this.tagName`).Str())
+	t.Len(p.MustElementsL("form >> text=nothing"), 0)
+
+	t.Eq("Save", p.Race().ElementL("testid=save").MustDo().MustText())
+
+	_, err := p.Sleeper(rod.NotFoundSleeper).ElementL("text=nothing")
+	t.Is(err, &rod.ErrElementNotFound{})
+}
+
 func (t T) ElementR() {
 	p := t.page.MustNavigate(t.srcFile("fixtures/selector.html"))
 	el := p.MustElementR("button", `\d1`)
@@ -0,0 +1,118 @@
+package rod
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// BrowserReconnected is emitted after the cdp client reconnects to the browser and the pages are resumed.
+// Use cdp.Client.Reconnect to enable the reconnection.
+type BrowserReconnected struct {
+	// Pages that are resumed
+	Pages []proto.TargetTargetID `json:"pages"`
+}
+
+// ProtoEvent interface
+func (evt BrowserReconnected) ProtoEvent() string {
+	return "Rod.browserReconnected"
+}
+
+// sessions maps the sessions the pages hold to the sessions of the current connection.
+// After reconnected the sessions of the pages are no longer valid, so that the pages can keep working.
+type sessions struct {
+	lock      *sync.Mutex
+	toCurrent map[proto.TargetSessionID]proto.TargetSessionID
+	toOrigin  map[proto.TargetSessionID]proto.TargetSessionID
+}
+
+func newSessions() *sessions {
+	return &sessions{
+		lock:      &sync.Mutex{},
+		toCurrent: map[proto.TargetSessionID]proto.TargetSessionID{},
+		toOrigin:  map[proto.TargetSessionID]proto.TargetSessionID{},
+	}
+}
+
+func (s *sessions) set(origin, current proto.TargetSessionID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.toOrigin, s.toCurrent[origin])
+	s.toCurrent[origin] = current
+	s.toOrigin[current] = origin
+}
+
+func (s *sessions) current(id proto.TargetSessionID) proto.TargetSessionID {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if current, has := s.toCurrent[id]; has {
+		return current
+	}
+	return id
+}
+
+func (s *sessions) origin(id proto.TargetSessionID) proto.TargetSessionID {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if origin, has := s.toOrigin[id]; has {
+		return origin
+	}
+	return id
+}
+
+// resume the pages and the enabled domains after the cdp client reconnected
+func (b *Browser) resume() {
+	relaunched := b.watchdog != nil && b.watchdog.reset()
+
+	_ = proto.TargetSetDiscoverTargets{Discover: true}.Call(b)
+
+	evt := &BrowserReconnected{Pages: []proto.TargetTargetID{}}
+
+	b.states.Range(func(key, value interface{}) bool {
+		page, ok := value.(*Page)
+		if !ok {
+			return true
+		}
+
+		res, err := proto.TargetAttachToTarget{
+			TargetID: page.TargetID,
+			Flatten:  true,
+		}.Call(b)
+		if err != nil && relaunched {
+			res, err = b.watchdog.restore(b, page)
+		}
+		if err != nil {
+			// the target is gone while disconnected
+			b.states.Delete(key)
+			return true
+		}
+
+		b.sessions.set(page.SessionID, res.SessionID)
+		evt.Pages = append(evt.Pages, page.TargetID)
+		return true
+	})
+
+	// replay the enabled domains, the pages use the same sessions as before via the Browser.Call
+	b.states.Range(func(key, value interface{}) bool {
+		k, ok := key.(stateKey)
+		if !ok {
+			return true
+		}
+
+		_, name := proto.ParseMethodName(k.methodName)
+		if name == "enable" {
+			_, _ = b.Call(b.ctx, string(k.sessionID), k.methodName, value)
+		}
+		return true
+	})
+
+	b.event.publish(&Message{
+		Method: evt.ProtoEvent(),
+		lock:   &sync.Mutex{},
+		event:  reflect.ValueOf(evt).Elem(),
+	})
+}
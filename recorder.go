@@ -0,0 +1,244 @@
+package rod
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/ysmood/gson"
+)
+
+// RecordedRequest is a request the RequestRecorder saw
+type RecordedRequest struct {
+	page *Page
+
+	// RequestID of the request
+	RequestID proto.NetworkRequestID
+
+	// Request sent by the browser
+	Request *proto.NetworkRequest
+
+	// ResourceType of the request
+	ResourceType proto.NetworkResourceType
+
+	// Response of the request, nil if the response hasn't been received yet
+	Response *proto.NetworkResponse
+
+	// Finished is true when the loading of the request is finished or failed
+	Finished bool
+
+	// ErrorText of the failed request
+	ErrorText string
+
+	body *recordedBody
+}
+
+type recordedBody struct {
+	once sync.Once
+	val  string
+}
+
+// Method of the request
+func (r *RecordedRequest) Method() string {
+	return r.Request.Method
+}
+
+// URL of the request
+func (r *RecordedRequest) URL() string {
+	return r.Request.URL
+}
+
+// Body of the request, it will be fetched from the browser if the body is too long
+// to be included in the event.
+func (r *RecordedRequest) Body() string {
+	r.body.once.Do(func() {
+		r.body.val = r.Request.PostData
+		if r.body.val == "" && r.Request.HasPostData {
+			res, err := proto.NetworkGetRequestPostData{RequestID: r.RequestID}.Call(r.page)
+			if err == nil {
+				r.body.val = res.PostData
+			}
+		}
+	})
+	return r.body.val
+}
+
+// JSONBody of the request
+func (r *RecordedRequest) JSONBody() gson.JSON {
+	return gson.NewFrom(r.Body())
+}
+
+// RequestMatcher to match the recorded requests.
+// A request must match all the non-empty fields.
+type RequestMatcher struct {
+	// Method of the request, such as "POST", case insensitive
+	Method string
+
+	// Includes and Excludes are the url regexps, the same as Page.WaitRequestIdle
+	Includes []string
+	Excludes []string
+
+	// Filter the request with custom logic, such as to check the RecordedRequest.JSONBody
+	Filter func(*RecordedRequest) bool
+}
+
+func (m *RequestMatcher) matcher() func(*RecordedRequest) bool {
+	if m == nil {
+		return func(*RecordedRequest) bool { return true }
+	}
+
+	includes := m.Includes
+	if len(includes) == 0 {
+		includes = []string{""}
+	}
+	match := genRegMatcher(includes, m.Excludes)
+
+	return func(r *RecordedRequest) bool {
+		if m.Method != "" && !strings.EqualFold(m.Method, r.Method()) {
+			return false
+		}
+		if !match(r.URL()) {
+			return false
+		}
+		return m.Filter == nil || m.Filter(r)
+	}
+}
+
+// RequestRecorder keeps a queryable log of the requests of a page
+type RequestRecorder struct {
+	ctx   context.Context
+	state *requestRecorderState
+}
+
+type requestRecorderState struct {
+	changeNotifier
+	list []*RecordedRequest
+	stop func()
+}
+
+// RecordRequests starts to record the requests of the page until the RequestRecorder.Stop is called.
+// It only listens to the Network events, it won't interfere with the HijackRouter.
+func (p *Page) RecordRequests() *RequestRecorder {
+	page := p
+	p, cancel := p.WithCancel()
+
+	s := &requestRecorderState{
+		changeNotifier: newChangeNotifier(),
+		stop:           cancel,
+	}
+
+	latest := map[proto.NetworkRequestID]*RecordedRequest{}
+
+	update := func(id proto.NetworkRequestID, fn func(*RecordedRequest)) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		if r, has := latest[id]; has {
+			fn(r)
+			s.notify()
+		}
+	}
+
+	wait := p.EachEvent(func(e *proto.NetworkRequestWillBeSent) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		r := &RecordedRequest{
+			page:         page,
+			RequestID:    e.RequestID,
+			Request:      e.Request,
+			ResourceType: e.Type,
+			body:         &recordedBody{},
+		}
+		latest[e.RequestID] = r
+		s.list = append(s.list, r)
+		s.notify()
+	}, func(e *proto.NetworkResponseReceived) {
+		update(e.RequestID, func(r *RecordedRequest) { r.Response = e.Response })
+	}, func(e *proto.NetworkLoadingFinished) {
+		update(e.RequestID, func(r *RecordedRequest) { r.Finished = true })
+	}, func(e *proto.NetworkLoadingFailed) {
+		update(e.RequestID, func(r *RecordedRequest) {
+			r.Finished = true
+			r.ErrorText = e.ErrorText
+		})
+	})
+
+	go wait()
+
+	return &RequestRecorder{ctx: page.ctx, state: s}
+}
+
+// Context returns a clone with the specified ctx for RequestRecorder.WaitFor
+func (r *RequestRecorder) Context(ctx context.Context) *RequestRecorder {
+	newObj := *r
+	newObj.ctx = ctx
+	return &newObj
+}
+
+// Timeout returns a clone with the specified timeout for RequestRecorder.WaitFor
+func (r *RequestRecorder) Timeout(d time.Duration) *RequestRecorder {
+	ctx, cancel := context.WithTimeout(r.ctx, d)
+	return r.Context(context.WithValue(ctx, timeoutContextKey{}, &timeoutContextVal{r.ctx, cancel}))
+}
+
+// Stop recording, the recorded requests can still be queried
+func (r *RequestRecorder) Stop() {
+	r.state.stop()
+}
+
+// All the recorded requests in the order they are sent
+func (r *RequestRecorder) All() []*RecordedRequest {
+	return r.Filter(nil)
+}
+
+// Filter returns the recorded requests that match the matcher, nil matcher matches all
+func (r *RequestRecorder) Filter(m *RequestMatcher) []*RecordedRequest {
+	return filterRecordedRequests(r.state.snapshot(), m.matcher())
+}
+
+// Count of the recorded requests that match the matcher, nil matcher matches all
+func (r *RequestRecorder) Count(m *RequestMatcher) int {
+	return len(r.Filter(m))
+}
+
+// WaitFor waits until a recorded request matches the matcher, nil matcher matches all.
+// The matcher will be checked again each time a request is sent or its response is updated,
+// so the Filter can also be used to wait for the response, such as checking RecordedRequest.Response.
+func (r *RequestRecorder) WaitFor(m *RequestMatcher) (*RecordedRequest, error) {
+	match := m.matcher()
+
+	var found *RecordedRequest
+	err := r.state.wait(r.ctx, func() bool {
+		if list := filterRecordedRequests(r.state.snapshot(), match); len(list) > 0 {
+			found = list[0]
+		}
+		return found != nil
+	})
+	return found, err
+}
+
+// copy the requests so that the updates from the events won't race with the readers
+func (s *requestRecorderState) snapshot() []*RecordedRequest {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	list := make([]*RecordedRequest, len(s.list))
+	for i, req := range s.list {
+		cp := *req
+		list[i] = &cp
+	}
+	return list
+}
+
+func filterRecordedRequests(list []*RecordedRequest, match func(*RecordedRequest) bool) []*RecordedRequest {
+	found := []*RecordedRequest{}
+	for _, req := range list {
+		if match(req) {
+			found = append(found, req)
+		}
+	}
+	return found
+}
@@ -0,0 +1,54 @@
+package rod_test
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+func (t T) RecordRequests() {
+	s := t.Serve()
+	s.Route("/", ".html", `<html><body><script>
+		function req(id) {
+			return fetch('/api/x', { method: 'POST', body: JSON.stringify({ id }) })
+		}
+	</script></body></html>`)
+	s.Route("/api/x", ".json", `{}`)
+
+	p := t.newPage()
+	rec := p.RecordRequests()
+	defer rec.Stop()
+
+	p.MustNavigate(s.URL()).MustWaitLoad()
+	p.MustEval(`() => Promise.all([req(1), req(2), req(1)])`)
+
+	api := &rod.RequestMatcher{
+		Method:   http.MethodPost,
+		Includes: []string{`/api/x$`},
+	}
+	id1 := &rod.RequestMatcher{
+		Method:   http.MethodPost,
+		Includes: []string{`/api/x$`},
+		Filter: func(r *rod.RecordedRequest) bool {
+			return r.JSONBody().Get("id").Int() == 1
+		},
+	}
+
+	req := rec.MustWaitFor(id1)
+	t.Eq(s.URL("/api/x"), req.URL())
+	t.Eq(`{"id":1}`, req.Body())
+
+	t.Eq(3, rec.Count(api))
+	t.Eq(2, rec.Count(id1))
+	t.Eq(s.URL("/"), rec.All()[0].URL())
+	t.Eq(200, rec.All()[0].Response.Status)
+
+	rec.Stop()
+	p.MustEval(`() => req(1)`)
+	t.Eq(3, rec.Count(api))
+
+	_, err := rec.Timeout(time.Millisecond).WaitFor(&rod.RequestMatcher{Method: "PUT"})
+	t.Eq(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,327 @@
+package rod
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/go-rod/rod/lib/utils"
+)
+
+// ScreencastOptions for Page.StartScreencast
+type ScreencastOptions struct {
+	// Format of the frames, default is jpeg
+	Format proto.PageStartScreencastFormat
+
+	// Quality of the jpeg frames, from 0 to 100, default is 80
+	Quality int
+
+	// MaxWidth and MaxHeight of the frames, zero means the size of the viewport
+	MaxWidth  int
+	MaxHeight int
+
+	// EveryNthFrame only sends every n-th frame, default is 1
+	EveryNthFrame int
+
+	// Buffer size of the frame channel, default is 16. When the buffer is full the browser will
+	// stop sending new frames until the buffered ones are consumed.
+	Buffer int
+}
+
+func (opts *ScreencastOptions) init() *ScreencastOptions {
+	o := ScreencastOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Format == "" {
+		o.Format = proto.PageStartScreencastFormatJpeg
+	}
+	if o.Quality == 0 {
+		o.Quality = 80
+	}
+	if o.EveryNthFrame == 0 {
+		o.EveryNthFrame = 1
+	}
+	if o.Buffer == 0 {
+		o.Buffer = 16
+	}
+	return &o
+}
+
+// ScreencastFrame is a frame of the Screencast
+type ScreencastFrame struct {
+	// Data of the image, its format is the ScreencastOptions.Format
+	Data []byte
+
+	// Format of the Data
+	Format proto.PageStartScreencastFormat
+
+	// Time when the frame is painted
+	Time time.Time
+
+	// Metadata of the viewport when the frame is painted
+	Metadata *proto.PageScreencastFrameMetadata
+}
+
+// Image decodes the Data
+func (f *ScreencastFrame) Image() (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(f.Data))
+	return img, err
+}
+
+// Screencast records the frames of a page, created by Page.StartScreencast
+type Screencast struct {
+	page   *Page
+	cancel func()
+	sub    *Subscription
+	frames chan *ScreencastFrame
+	stop   sync.Once
+}
+
+// StartScreencast starts to receive the frames of the page. The browser only sends a new frame when
+// the page is repainted, so there may be no frame for a static page. Use Screencast.Stop to end it.
+func (p *Page) StartScreencast(opts *ScreencastOptions) (*Screencast, error) {
+	opts = opts.init()
+
+	p, cancel := p.WithCancel()
+
+	s := &Screencast{
+		page:   p,
+		cancel: cancel,
+		frames: make(chan *ScreencastFrame, opts.Buffer),
+	}
+
+	s.sub = p.Subscribe(EventFilter{}, func(e *proto.PageScreencastFrame) {
+		frame := &ScreencastFrame{
+			Data:     e.Data,
+			Format:   opts.Format,
+			Time:     time.Now(),
+			Metadata: e.Metadata,
+		}
+		if e.Metadata != nil && e.Metadata.Timestamp != 0 {
+			frame.Time = e.Metadata.Timestamp.Time()
+		}
+
+		select {
+		case <-p.ctx.Done():
+			return
+		case s.frames <- frame:
+		}
+
+		// the browser won't send the next frame until the current one is acknowledged
+		_ = proto.PageScreencastFrameAck{SessionID: e.SessionID}.Call(p)
+	})
+
+	go func() {
+		<-s.sub.Done()
+		close(s.frames)
+	}()
+
+	err := proto.PageStartScreencast{
+		Format:        opts.Format,
+		Quality:       opts.Quality,
+		MaxWidth:      opts.MaxWidth,
+		MaxHeight:     opts.MaxHeight,
+		EveryNthFrame: opts.EveryNthFrame,
+	}.Call(p)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Frames returns the channel of the frames, it will be closed after the screencast is stopped
+func (s *Screencast) Frames() <-chan *ScreencastFrame {
+	return s.frames
+}
+
+// Stop the screencast, it's safe to call it multiple times
+func (s *Screencast) Stop() (err error) {
+	s.stop.Do(func() {
+		err = proto.PageStopScreencast{}.Call(s.page)
+		s.cancel()
+		<-s.sub.Done()
+	})
+	return
+}
+
+// Record the frames to the writer until the screencast is stopped, then the writer will be closed
+func (s *Screencast) Record(w FrameWriter) error {
+	for frame := range s.frames {
+		err := w.WriteFrame(frame)
+		if err != nil {
+			// drain the frames so that the screencast won't be blocked
+			go func() {
+				for range s.frames {
+				}
+			}()
+			_ = w.Close()
+			return err
+		}
+	}
+	return w.Close()
+}
+
+// RecordScreencast is a shortcut for Page.StartScreencast and Screencast.Record, such as:
+//
+//     stop := page.MustRecordScreencast(nil, rod.NewGIFWriter(file))
+//     defer stop()
+//
+// Call the stop to end the recording, it waits until the writer is closed.
+func (p *Page) RecordScreencast(opts *ScreencastOptions, w FrameWriter) (stop func() error, err error) {
+	s, err := p.StartScreencast(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- s.Record(w) }()
+
+	return func() error {
+		err := s.Stop()
+		recErr := <-done
+		if err != nil {
+			return err
+		}
+		return recErr
+	}, nil
+}
+
+// FrameWriter writes the frames of a Screencast into a video format
+type FrameWriter interface {
+	WriteFrame(*ScreencastFrame) error
+
+	// Close flushes the buffered data, it won't close the underlying writer
+	Close() error
+}
+
+// NewGIFWriter encodes the frames as an animated GIF. The delay of each frame is the time gap
+// to the next frame. Because GIF can't be streamed, all the frames are buffered until the Close.
+func NewGIFWriter(w io.Writer) FrameWriter {
+	return &gifWriter{w: w, anim: &gif.GIF{}}
+}
+
+type gifWriter struct {
+	w     io.Writer
+	anim  *gif.GIF
+	times []time.Time
+}
+
+func (g *gifWriter) WriteFrame(frame *ScreencastFrame) error {
+	img, err := frame.Image()
+	if err != nil {
+		return err
+	}
+
+	bounds := img.Bounds()
+	paletted := image.NewPaletted(bounds, palette.Plan9)
+	draw.FloydSteinberg.Draw(paletted, bounds, img, bounds.Min)
+
+	if bounds.Dx() > g.anim.Config.Width {
+		g.anim.Config.Width = bounds.Dx()
+	}
+	if bounds.Dy() > g.anim.Config.Height {
+		g.anim.Config.Height = bounds.Dy()
+	}
+
+	g.anim.Image = append(g.anim.Image, paletted)
+	g.times = append(g.times, frame.Time)
+	return nil
+}
+
+func (g *gifWriter) Close() error {
+	if len(g.anim.Image) == 0 {
+		return nil
+	}
+
+	g.anim.Delay = make([]int, len(g.times))
+	for i := range g.times {
+		// in 100ths of a second, the last frame will hold for a second
+		delay := 100
+		if i+1 < len(g.times) {
+			delay = int(g.times[i+1].Sub(g.times[i]) / (10 * time.Millisecond))
+		}
+		if delay < 2 {
+			// most viewers treat a delay less than 2 as 10
+			delay = 2
+		}
+		g.anim.Delay[i] = delay
+	}
+
+	return gif.EncodeAll(g.w, g.anim)
+}
+
+// NewMJPEGWriter concatenates the frames as a Motion JPEG stream, the png frames will be converted to jpeg.
+// Most video players and tools like ffmpeg can play or convert it.
+func NewMJPEGWriter(w io.Writer) FrameWriter {
+	return &mjpegWriter{w: w}
+}
+
+type mjpegWriter struct {
+	w io.Writer
+}
+
+func (m *mjpegWriter) WriteFrame(frame *ScreencastFrame) error {
+	if frame.Format == proto.PageStartScreencastFormatJpeg {
+		_, err := m.w.Write(frame.Data)
+		return err
+	}
+
+	img, err := frame.Image()
+	if err != nil {
+		return err
+	}
+	return jpeg.Encode(m.w, img, &jpeg.Options{Quality: 90})
+}
+
+func (m *mjpegWriter) Close() error {
+	return nil
+}
+
+// NewPNGSequenceWriter saves each frame as a png file in the dir, the file names are the frame
+// numbers, such as "00000.png", "00001.png", the jpeg frames will be converted to png.
+func NewPNGSequenceWriter(dir string) FrameWriter {
+	return &pngSequenceWriter{dir: dir}
+}
+
+type pngSequenceWriter struct {
+	dir   string
+	count int
+}
+
+func (s *pngSequenceWriter) WriteFrame(frame *ScreencastFrame) error {
+	data := frame.Data
+
+	if frame.Format != proto.PageStartScreencastFormatPng {
+		img, err := frame.Image()
+		if err != nil {
+			return err
+		}
+		buf := bytes.NewBuffer(nil)
+		err = png.Encode(buf, img)
+		if err != nil {
+			return err
+		}
+		data = buf.Bytes()
+	}
+
+	path := filepath.Join(s.dir, fmt.Sprintf("%05d.png", s.count))
+	s.count++
+	return utils.OutputFile(path, data)
+}
+
+func (s *pngSequenceWriter) Close() error {
+	return nil
+}
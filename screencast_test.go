@@ -0,0 +1,131 @@
+package rod_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/go-rod/rod/lib/utils"
+)
+
+func (t T) Screencast() {
+	p := t.newPage(t.srcFile("fixtures/screencast.html")).MustWaitLoad()
+
+	s := p.MustStartScreencast()
+	frame := <-s.Frames()
+	t.Eq(proto.PageStartScreencastFormatJpeg, frame.Format)
+	img, err := frame.Image()
+	t.E(err)
+	t.Gt(img.Bounds().Dx(), 0)
+
+	s.MustStop()
+	s.MustStop()
+
+	// the channel should be closed after the stop
+	for range s.Frames() {
+	}
+
+	buf := bytes.NewBuffer(nil)
+	stop := p.MustRecordScreencast(&rod.ScreencastOptions{MaxWidth: 320}, rod.NewGIFWriter(buf))
+	utils.Sleep(0.5)
+	stop()
+	anim, err := gif.DecodeAll(buf)
+	t.E(err)
+	t.Gt(len(anim.Image), 0)
+
+	t.Panic(func() {
+		t.mc.stubErr(1, proto.PageStartScreencast{})
+		p.MustStartScreencast()
+	})
+	t.Panic(func() {
+		t.mc.stubErr(1, proto.PageStartScreencast{})
+		p.MustRecordScreencast(nil, rod.NewMJPEGWriter(buf))
+	})
+}
+
+func (t T) ScreencastWriters() {
+	frame := func(format proto.PageStartScreencastFormat, c color.Color, at time.Time) *rod.ScreencastFrame {
+		img := image.NewNRGBA(image.Rect(0, 0, 4, 3))
+		for y := 0; y < 3; y++ {
+			for x := 0; x < 4; x++ {
+				img.Set(x, y, c)
+			}
+		}
+
+		buf := bytes.NewBuffer(nil)
+		if format == proto.PageStartScreencastFormatPng {
+			t.E(png.Encode(buf, img))
+		} else {
+			t.E(jpeg.Encode(buf, img, nil))
+		}
+		return &rod.ScreencastFrame{Data: buf.Bytes(), Format: format, Time: at}
+	}
+
+	now := time.Now()
+	frames := []*rod.ScreencastFrame{
+		frame(proto.PageStartScreencastFormatJpeg, color.White, now),
+		frame(proto.PageStartScreencastFormatPng, color.Black, now.Add(300*time.Millisecond)),
+	}
+
+	{ // gif
+		buf := bytes.NewBuffer(nil)
+		w := rod.NewGIFWriter(buf)
+		for _, f := range frames {
+			t.E(w.WriteFrame(f))
+		}
+		t.E(w.Close())
+
+		anim, err := gif.DecodeAll(buf)
+		t.E(err)
+		t.Len(anim.Image, 2)
+		t.Eq([]int{30, 100}, anim.Delay)
+		t.Eq(4, anim.Config.Width)
+
+		t.Err(w.WriteFrame(&rod.ScreencastFrame{Data: []byte("invalid")}))
+		t.Nil(rod.NewGIFWriter(buf).Close())
+	}
+
+	{ // mjpeg
+		buf := bytes.NewBuffer(nil)
+		w := rod.NewMJPEGWriter(buf)
+		for _, f := range frames {
+			t.E(w.WriteFrame(f))
+		}
+		t.E(w.Close())
+
+		t.Eq(2, bytes.Count(buf.Bytes(), []byte("\xff\xd8\xff"))) // the start markers of the frames
+
+		first, err := jpeg.Decode(buf)
+		t.E(err)
+		t.Eq(4, first.Bounds().Dx())
+
+		t.Err(w.WriteFrame(&rod.ScreencastFrame{Data: []byte("invalid")}))
+	}
+
+	{ // png sequence
+		dir := filepath.Join("tmp", "screencast", t.Srand(8))
+		w := rod.NewPNGSequenceWriter(dir)
+		for _, f := range frames {
+			t.E(w.WriteFrame(f))
+		}
+		t.E(w.Close())
+
+		for _, name := range []string{"00000.png", "00001.png"} {
+			f, err := os.Open(filepath.Join(dir, name))
+			t.E(err)
+			_, err = png.Decode(f)
+			t.E(err)
+			t.E(f.Close())
+		}
+
+		t.Err(w.WriteFrame(&rod.ScreencastFrame{Data: []byte("invalid")}))
+	}
+}
@@ -0,0 +1,216 @@
+package rod
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// StitchOptions for Page.ScreenshotStitched
+type StitchOptions struct {
+	// Format of the output image, png or jpeg, default is png
+	Format proto.PageCaptureScreenshotFormat
+
+	// Quality of the jpeg output, from 0 to 100, default is 90
+	Quality int
+
+	// HideFixed hides the elements that are position fixed or sticky after the first tile is captured,
+	// so that headers and footers won't repeat in every tile. They will be restored after the capture.
+	HideFixed bool
+
+	// ScrollDelay to wait after each scroll for the lazy-loaded content and animations, default is 100ms
+	ScrollDelay time.Duration
+
+	// MaxHeight of the capture in css pixels, it prevents the infinite scrolling pages from
+	// loading forever, default is 50000
+	MaxHeight int
+
+	// MaxBytes of the stitched image in memory, each device pixel takes 4 bytes, default is 512MB.
+	// If the image of the page will exceed it, ErrStitchTooLarge is returned before the canvas is allocated.
+	MaxBytes int
+}
+
+func (opts *StitchOptions) init() *StitchOptions {
+	o := StitchOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Format == "" {
+		o.Format = proto.PageCaptureScreenshotFormatPng
+	}
+	if o.Quality == 0 {
+		o.Quality = 90
+	}
+	if o.ScrollDelay == 0 {
+		o.ScrollDelay = 100 * time.Millisecond
+	}
+	if o.MaxHeight == 0 {
+		o.MaxHeight = 50000
+	}
+	if o.MaxBytes == 0 {
+		o.MaxBytes = 512 << 20
+	}
+	return &o
+}
+
+// ScreenshotStitched captures the full height of the page by scrolling through it and stitching the
+// viewport sized tiles together. Unlike Page.Screenshot with fullpage, it doesn't resize the viewport,
+// so it works for the pages that are taller than the texture limit of the browser, and the scroll
+// triggers the lazy-loaded content. The width of the output is the same as the viewport.
+func (p *Page) ScreenshotStitched(opts *StitchOptions) ([]byte, error) {
+	opts = opts.init()
+
+	origin, err := p.Eval(`() => [window.scrollX, window.scrollY]`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { // try to recover the scroll position
+		pos := origin.Value.Arr()
+		_, _ = p.Eval(`(x, y) => window.scrollTo(x, y)`, pos[0].Num(), pos[1].Num())
+	}()
+
+	height, err := p.stitchLoad(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics, err := proto.PageGetLayoutMetrics{}.Call(p)
+	if err != nil {
+		return nil, err
+	}
+	view := metrics.LayoutViewport
+	if view.ClientWidth == 0 || view.ClientHeight == 0 {
+		return nil, &ErrElementNotFound{}
+	}
+
+	var canvas *image.NRGBA
+	var scale float64
+
+	for y := 0; y < height; y += view.ClientHeight {
+		top, err := p.stitchScroll(y, opts.ScrollDelay)
+		if err != nil {
+			return nil, err
+		}
+
+		// the last tile may be shorter than the viewport if the MaxHeight is reached
+		h := math.Min(float64(view.ClientHeight), float64(height)-top)
+
+		shot, err := proto.PageCaptureScreenshot{
+			Format: proto.PageCaptureScreenshotFormatPng,
+			Clip: &proto.PageViewport{
+				X:      float64(view.PageX),
+				Y:      top,
+				Width:  float64(view.ClientWidth),
+				Height: h,
+				Scale:  1,
+			},
+		}.Call(p)
+		if err != nil {
+			return nil, err
+		}
+
+		tile, err := png.Decode(bytes.NewReader(shot.Data))
+		if err != nil {
+			return nil, err
+		}
+
+		if canvas == nil {
+			// the tiles are in device pixels
+			scale = float64(tile.Bounds().Dx()) / float64(view.ClientWidth)
+			w, h := tile.Bounds().Dx(), int(math.Round(float64(height)*scale))
+			if float64(w)*float64(h)*4 > float64(opts.MaxBytes) {
+				return nil, &ErrStitchTooLarge{Width: w, Height: h, MaxBytes: opts.MaxBytes}
+			}
+			canvas = image.NewNRGBA(image.Rect(0, 0, w, h))
+
+			if opts.HideFixed {
+				_, err = p.Eval(`() => {
+					for (const el of document.querySelectorAll('*')) {
+						const pos = getComputedStyle(el).position
+						if (pos === 'fixed' || pos === 'sticky') {
+							el.setAttribute('data-rod-stitch', el.style.visibility)
+							el.style.visibility = 'hidden'
+						}
+					}
+				}`)
+				if err != nil {
+					return nil, err
+				}
+				defer func() {
+					_, _ = p.Eval(`() => {
+						for (const el of document.querySelectorAll('[data-rod-stitch]')) {
+							el.style.visibility = el.getAttribute('data-rod-stitch')
+							el.removeAttribute('data-rod-stitch')
+						}
+					}`)
+				}()
+			}
+		}
+
+		at := image.Pt(0, int(math.Round(top*scale)))
+		draw.Draw(canvas, tile.Bounds().Sub(tile.Bounds().Min).Add(at), tile, tile.Bounds().Min, draw.Src)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if opts.Format == proto.PageCaptureScreenshotFormatJpeg {
+		err = jpeg.Encode(buf, canvas, &jpeg.Options{Quality: opts.Quality})
+	} else {
+		err = png.Encode(buf, canvas)
+	}
+	return buf.Bytes(), err
+}
+
+// scrolls to the end of the page step by step to trigger the lazy loading,
+// returns the final height of the page in css pixels
+func (p *Page) stitchLoad(opts *StitchOptions) (int, error) {
+	y := 0
+	for {
+		_, err := p.stitchScroll(y, opts.ScrollDelay)
+		if err != nil {
+			return 0, err
+		}
+
+		res, err := p.Eval(`() => [
+			window.scrollY + window.innerHeight,
+			Math.max(document.documentElement.scrollHeight, document.body ? document.body.scrollHeight : 0),
+			window.innerHeight,
+		]`)
+		if err != nil {
+			return 0, err
+		}
+		list := res.Value.Arr()
+		bottom, height, step := list[0].Int(), list[1].Int(), list[2].Int()
+
+		if height > opts.MaxHeight {
+			return opts.MaxHeight, nil
+		}
+		if bottom >= height || step == 0 {
+			return height, nil
+		}
+		y += step
+	}
+}
+
+// scrolls the window to y, returns the actual scroll top after the page is repainted
+func (p *Page) stitchScroll(y int, delay time.Duration) (float64, error) {
+	res, err := p.Eval(`y => { window.scrollTo(window.scrollX, y); return window.scrollY }`, y)
+	if err != nil {
+		return 0, err
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-p.ctx.Done():
+		return 0, p.ctx.Err()
+	case <-t.C:
+	}
+
+	return res.Value.Num(), p.WaitRepaint()
+}
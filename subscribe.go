@@ -0,0 +1,189 @@
+package rod
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// EventFilter for Browser.Subscribe and Browser.WaitEventMatch. The zero value matches all the events.
+type EventFilter struct {
+	// SessionID of the event, such as the Page.SessionID
+	SessionID proto.TargetSessionID
+
+	// TargetID matches the events from the session of the target, or the events about the target,
+	// such as the proto.TargetTargetInfoChanged of it.
+	TargetID proto.TargetTargetID
+
+	// FrameID matches the events that have the "frameId" or "frame.id" field equals to it,
+	// such as the proto.NetworkRequestWillBeSent and proto.PageFrameNavigated.
+	FrameID proto.PageFrameID
+
+	// Match is called before the event is loaded into the callback, return false to skip the event
+	Match func(*Message) bool
+}
+
+func (f EventFilter) match(b *Browser, msg *Message) bool {
+	if f.SessionID != "" && msg.SessionID != f.SessionID {
+		return false
+	}
+
+	if f.TargetID != "" || f.FrameID != "" {
+		ids := msg.ids()
+
+		if f.TargetID != "" && ids.targetID() != f.TargetID {
+			page := b.loadCachedPage(f.TargetID)
+			if page == nil || page.SessionID != msg.SessionID {
+				return false
+			}
+		}
+
+		if f.FrameID != "" && ids.frameID() != f.FrameID {
+			return false
+		}
+	}
+
+	if f.Match != nil && !f.Match(msg) {
+		return false
+	}
+
+	return true
+}
+
+// the common fields of the events to identify their targets and frames
+type eventIDs struct {
+	TargetID   proto.TargetTargetID `json:"targetId"`
+	TargetInfo *struct {
+		TargetID proto.TargetTargetID `json:"targetId"`
+	} `json:"targetInfo"`
+
+	FrameID proto.PageFrameID `json:"frameId"`
+	Frame   *struct {
+		ID proto.PageFrameID `json:"id"`
+	} `json:"frame"`
+}
+
+func (ids *eventIDs) targetID() proto.TargetTargetID {
+	if ids.TargetInfo != nil {
+		return ids.TargetInfo.TargetID
+	}
+	return ids.TargetID
+}
+
+func (ids *eventIDs) frameID() proto.PageFrameID {
+	if ids.Frame != nil {
+		return ids.Frame.ID
+	}
+	return ids.FrameID
+}
+
+func (msg *Message) ids() *eventIDs {
+	msg.lock.Lock()
+	defer msg.lock.Unlock()
+
+	data := msg.data
+	if data == nil {
+		data, _ = json.Marshal(msg.event.Interface())
+	}
+
+	ids := &eventIDs{}
+	_ = json.Unmarshal(data, ids)
+	return ids
+}
+
+// Subscription of the events, created by Browser.Subscribe or Page.Subscribe
+type Subscription struct {
+	cancel  func()
+	done    chan struct{}
+	stopped bool
+	err     error
+}
+
+// Subscribe the events that match the filter. The callbacks are the same as the ones of Page.EachEvent,
+// they will be called in a background goroutine until a callback returns true, the Unsubscribe is called,
+// or the context of the browser is done.
+func (b *Browser) Subscribe(filter EventFilter, callbacks ...interface{}) *Subscription {
+	b, cancel := b.WithCancel()
+	wait := b.listen(filter, callbacks)
+
+	s := &Subscription{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(s.done)
+		s.stopped, s.err = wait()
+	}()
+
+	return s
+}
+
+// Subscribe is similar to Browser.Subscribe, but only the events of the page will be matched
+func (p *Page) Subscribe(filter EventFilter, callbacks ...interface{}) *Subscription {
+	filter.SessionID = p.SessionID
+	return p.browser.Context(p.ctx).Subscribe(filter, callbacks...)
+}
+
+// Unsubscribe stops the subscription and waits for the running callback to return
+func (s *Subscription) Unsubscribe() {
+	s.cancel()
+	<-s.done
+}
+
+// Done is closed when the subscription ends
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Stopped returns true if the subscription is ended by a callback that returns true.
+// It's only meaningful after the Done is closed.
+func (s *Subscription) Stopped() bool {
+	<-s.done
+	return s.stopped
+}
+
+// Err returns why the subscription ends other than the Unsubscribe or the context, such as the ErrEventOverflow.
+// It's only meaningful after the Done is closed.
+func (s *Subscription) Err() error {
+	<-s.done
+	return s.err
+}
+
+// WaitEventMatch waits until the predicate returns true. The type of the predicate is the same as the
+// callback of the Page.EachEvent, such as:
+//
+//     browser.WaitEventMatch(rod.EventFilter{}, time.Minute, func(e *proto.NetworkResponseReceived) bool {
+//         return strings.Contains(e.Response.URL, "/api")
+//     })
+//
+// If the timeout is zero, it will only wait until the context of the browser is done.
+// The context error will be returned if the predicate never returns true,
+// or the ErrEventOverflow if the event buffer of the browser overflows with the EventOverflowError policy.
+func (b *Browser) WaitEventMatch(filter EventFilter, timeout time.Duration, predicate interface{}) error {
+	ctx := b.ctx
+	if timeout > 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	s := b.Context(ctx).Subscribe(filter, predicate)
+	if s.Stopped() {
+		return nil
+	}
+
+	if err := s.Err(); err != nil {
+		return err
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return &ErrEventStreamClosed{}
+}
+
+// WaitEventMatch is similar to Browser.WaitEventMatch, but only the events of the page will be matched
+func (p *Page) WaitEventMatch(filter EventFilter, timeout time.Duration, predicate interface{}) error {
+	defer p.tryTrace(TraceTypeWait, "event match")()
+	filter.SessionID = p.SessionID
+	return p.browser.Context(p.ctx).WaitEventMatch(filter, timeout, predicate)
+}
@@ -0,0 +1,244 @@
+package rod
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// TraceEvent is an action recorded by the TraceLog
+type TraceEvent struct {
+	Type TraceType `json:"type"`
+
+	// Message of the action, such as "left click", "load"
+	Message string `json:"message"`
+
+	// Selector of the query, such as `rod.element("button")`
+	Selector string `json:"selector,omitempty"`
+
+	// Element that the action is performed on
+	Element string `json:"element,omitempty"`
+
+	PageID proto.TargetTargetID `json:"pageId"`
+
+	Start time.Time `json:"start"`
+
+	// End is zero if the action is still running
+	End time.Time `json:"end"`
+
+	// Before and After are the jpeg screenshots of the page, only available when TraceLog.Screenshots is true
+	Before []byte `json:"before,omitempty"`
+	After  []byte `json:"after,omitempty"`
+}
+
+// Duration of the action
+func (e *TraceEvent) Duration() time.Duration {
+	if e.End.IsZero() {
+		return 0
+	}
+	return e.End.Sub(e.Start)
+}
+
+// TraceLog records the actions of the browser as structured events, such as queries, inputs, waits,
+// and navigations. Use Browser.TraceLog to enable it. Unlike the Browser.Trace, it doesn't print
+// anything or draw overlays on the page.
+type TraceLog struct {
+	// Screenshots before and after each action, it will slow down the actions
+	Screenshots bool
+
+	lock   sync.Mutex
+	start  time.Time
+	events []*TraceEvent
+}
+
+// NewTraceLog instance
+func NewTraceLog() *TraceLog {
+	return &TraceLog{start: time.Now()}
+}
+
+// Events returns a copy of the recorded events in the order they start
+func (l *TraceLog) Events() []TraceEvent {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	list := make([]TraceEvent, len(l.events))
+	for i, e := range l.events {
+		list[i] = *e
+	}
+	return list
+}
+
+// Reset removes all the recorded events
+func (l *TraceLog) Reset() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.start = time.Now()
+	l.events = nil
+}
+
+// WriteJSON writes the events as a JSON array
+func (l *TraceLog) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(l.Events())
+}
+
+// WriteChromeTrace writes the events in the Chrome trace event format, the output can be opened
+// by the "Performance" panel of the Chrome DevTools, https://ui.perfetto.dev or chrome://tracing .
+// Each page is a thread of the timeline, the screenshots are the snapshots of the timeline.
+// Doc: https://docs.google.com/document/d/1CvAClvFfyA5R-PhYUmn5OOQtYMH4h6I0nSsKchNAySU
+func (l *TraceLog) WriteChromeTrace(w io.Writer) error {
+	l.lock.Lock()
+	start := l.start
+	l.lock.Unlock()
+
+	events := l.Events()
+	now := time.Now()
+
+	ts := func(t time.Time) int64 { return t.Sub(start).Microseconds() }
+
+	threads := map[proto.TargetTargetID]int{}
+	list := []map[string]interface{}{{
+		"name": "process_name", "ph": "M", "pid": 1, "tid": 0,
+		"args": map[string]interface{}{"name": "rod"},
+	}}
+
+	tid := func(id proto.TargetTargetID) int {
+		if n, has := threads[id]; has {
+			return n
+		}
+		n := len(threads) + 1
+		threads[id] = n
+		list = append(list, map[string]interface{}{
+			"name": "thread_name", "ph": "M", "pid": 1, "tid": n,
+			"args": map[string]interface{}{"name": fmt.Sprintf("page %s", id)},
+		})
+		return n
+	}
+
+	snapshots := 0
+	snapshot := func(t time.Time, thread int, data []byte) {
+		if len(data) == 0 {
+			return
+		}
+		snapshots++
+		list = append(list, map[string]interface{}{
+			"name": "Screenshot", "cat": "disabled-by-default-devtools.screenshot",
+			"ph": "O", "id": fmt.Sprintf("0x%x", snapshots), "ts": ts(t), "pid": 1, "tid": thread,
+			"args": map[string]interface{}{"snapshot": data},
+		})
+	}
+
+	for _, e := range events {
+		thread := tid(e.PageID)
+
+		end := e.End
+		if end.IsZero() {
+			end = now
+		}
+
+		args := map[string]interface{}{"message": e.Message}
+		if e.Selector != "" {
+			args["selector"] = e.Selector
+		}
+		if e.Element != "" {
+			args["element"] = e.Element
+		}
+		if e.End.IsZero() {
+			args["unfinished"] = true
+		}
+
+		list = append(list, map[string]interface{}{
+			"name": strings.TrimSpace(e.Type.String() + " " + e.Message),
+			"cat":  string(e.Type),
+			"ph":   "X",
+			"ts":   ts(e.Start),
+			"dur":  end.Sub(e.Start).Microseconds(),
+			"pid":  1,
+			"tid":  thread,
+			"args": args,
+		})
+
+		snapshot(e.Start, thread, e.Before)
+		snapshot(end, thread, e.After)
+	}
+
+	return json.NewEncoder(w).Encode(map[string]interface{}{
+		"traceEvents":     list,
+		"displayTimeUnit": "ms",
+	})
+}
+
+func (l *TraceLog) add(e *TraceEvent) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.start.IsZero() {
+		l.start = e.Start
+	}
+	l.events = append(l.events, e)
+}
+
+func (l *TraceLog) finish(e *TraceEvent, after []byte) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	e.After = after
+	e.End = time.Now()
+}
+
+// TraceLog sets the log to record the actions, set it to nil to stop recording
+func (b *Browser) TraceLog(l *TraceLog) *Browser {
+	b.traceLog = l
+	return b
+}
+
+// records the action to the trace log if it's enabled, call the returned function when the action ends.
+// The event is only built when the trace log is enabled.
+func (p *Page) traceAction(event func() *TraceEvent) func() {
+	l := p.browser.traceLog
+	if l == nil {
+		return func() {}
+	}
+
+	e := event()
+	e.PageID = p.TargetID
+	e.Start = time.Now()
+	if l.Screenshots {
+		e.Before = p.traceScreenshot()
+	}
+	l.add(e)
+
+	return func() {
+		var after []byte
+		if l.Screenshots {
+			after = p.traceScreenshot()
+		}
+		l.finish(e, after)
+	}
+}
+
+func (p *Page) traceQuery(msg string, opts *EvalOptions) func() {
+	return p.traceAction(func() *TraceEvent {
+		return &TraceEvent{Type: TraceTypeQuery, Message: msg, Selector: opts.String()}
+	})
+}
+
+func (p *Page) traceScreenshot() []byte {
+	res, err := proto.PageCaptureScreenshot{
+		Format:  proto.PageCaptureScreenshotFormatJpeg,
+		Quality: 60,
+	}.Call(p)
+	if err != nil {
+		return nil
+	}
+	return res.Data
+}
+
+func traceMessage(msg []interface{}) string {
+	return strings.TrimSpace(fmt.Sprintln(msg...))
+}
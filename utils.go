@@ -114,7 +114,7 @@ func (pp PagePool) Cleanup(iteratee func(*Page)) {
 	}
 }
 
-var _ io.Reader = &StreamReader{}
+var _ io.ReadCloser = &StreamReader{}
 
 // StreamReader for browser data stream
 type StreamReader struct {
@@ -160,6 +160,11 @@ func (sr *StreamReader) Read(p []byte) (n int, err error) {
 	return sr.buf.Read(p)
 }
 
+// Close the stream, discard any temporary backing storage.
+func (sr *StreamReader) Close() error {
+	return proto.IOClose{Handle: sr.handle}.Call(sr.c)
+}
+
 // Try try fn with recover, return the panic as value
 func Try(fn func()) (err error) {
 	defer func() {
@@ -204,6 +209,43 @@ func genRegMatcher(includes, excludes []string) func(string) bool {
 	}
 }
 
+// changeNotifier is a lock that notifies the waiters when the state it guards changes, such as the state
+// of the RequestRecorder and the WebSockets
+type changeNotifier struct {
+	lock    *sync.Mutex
+	changed chan struct{}
+}
+
+func newChangeNotifier() changeNotifier {
+	return changeNotifier{lock: &sync.Mutex{}, changed: make(chan struct{})}
+}
+
+// notify the waiters that the state has changed, must be called with the lock held
+func (n *changeNotifier) notify() {
+	close(n.changed)
+	n.changed = make(chan struct{})
+}
+
+// wait until the check returns true. The check should read the state via a copy, it will be called again
+// each time the state changes, the ctx error will be returned if the ctx is done before that.
+func (n *changeNotifier) wait(ctx context.Context, check func() bool) error {
+	for {
+		n.lock.Lock()
+		changed := n.changed
+		n.lock.Unlock()
+
+		if check() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
 type saveFileType int
 
 const (
@@ -0,0 +1,241 @@
+package rod
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/go-rod/rod/lib/utils"
+)
+
+// VisualOptions for Page.MatchScreenshot and Element.MatchScreenshot
+type VisualOptions struct {
+	// Dir of the baseline screenshots, default is "testdata/screenshots"
+	Dir string
+
+	// OutDir for the actual screenshots and the diff images of the mismatched ones,
+	// default is "tmp/screenshots"
+	OutDir string
+
+	// Threshold of the perceptual color difference of a pixel, from 0 to 1, default is 0.1.
+	// Check utils.DiffImage for details.
+	Threshold float64
+
+	// Exact requires the pixels to be exactly the same, the Threshold is ignored
+	Exact bool
+
+	// Tolerance is the max ratio of the different pixels to pass the comparison, default is 0
+	Tolerance float64
+
+	// Masks are the css selectors of the dynamic regions to ignore, such as clocks and ads
+	Masks []string
+
+	// FullPage screenshot of the page, it's ignored by the Element.MatchScreenshot
+	FullPage bool
+
+	// Update the baseline with the actual screenshot instead of comparing
+	Update bool
+}
+
+func (opts *VisualOptions) init() *VisualOptions {
+	o := VisualOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Dir == "" {
+		o.Dir = filepath.FromSlash("testdata/screenshots")
+	}
+	if o.OutDir == "" {
+		o.OutDir = filepath.FromSlash("tmp/screenshots")
+	}
+	if o.Exact {
+		o.Threshold = 0
+	} else if o.Threshold == 0 {
+		o.Threshold = 0.1
+	}
+	return &o
+}
+
+// VisualResult of the screenshot comparison
+type VisualResult struct {
+	// Pass is true if the ratio of the different pixels is not greater than the tolerance
+	Pass bool
+
+	// Created is true if the baseline didn't exist or the VisualOptions.Update is true,
+	// the actual screenshot is saved as the baseline.
+	Created bool
+
+	// Baseline file path
+	Baseline string
+
+	// Actual file path, only saved when not pass
+	Actual string
+
+	// Diff image file path, only saved when not pass
+	Diff string
+
+	// DiffPixels is the count of the different pixels
+	DiffPixels int
+
+	// DiffRatio is the ratio of the different pixels to all the compared pixels
+	DiffRatio float64
+}
+
+// MatchScreenshot compares the screenshot of the page with the baseline file of the name,
+// if the baseline doesn't exist, the screenshot will be saved as the baseline.
+func (p *Page) MatchScreenshot(name string, opts *VisualOptions) (*VisualResult, error) {
+	opts = opts.init()
+
+	// the masks must be resolved after the viewport is resized for the full page
+	if opts.FullPage {
+		restore, err := p.fullPageViewport()
+		if err != nil {
+			return nil, err
+		}
+		defer restore()
+	}
+
+	masks, err := p.visualMasks(nil, opts.Masks)
+	if err != nil {
+		return nil, err
+	}
+
+	bin, err := p.Screenshot(false, &proto.PageCaptureScreenshot{
+		Format: proto.PageCaptureScreenshotFormatPng,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return matchScreenshot(name, bin, masks, opts)
+}
+
+// MatchScreenshot compares the screenshot of the element with the baseline file of the name,
+// if the baseline doesn't exist, the screenshot will be saved as the baseline.
+func (el *Element) MatchScreenshot(name string, opts *VisualOptions) (*VisualResult, error) {
+	opts = opts.init()
+
+	bin, err := el.Screenshot(proto.PageCaptureScreenshotFormatPng, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	masks, err := el.page.visualMasks(el, opts.Masks)
+	if err != nil {
+		return nil, err
+	}
+
+	return matchScreenshot(name, bin, masks, opts)
+}
+
+// returns the boxes of the masks in the pixel coordinates of the screenshot
+func (p *Page) visualMasks(el *Element, selectors []string) ([]image.Rectangle, error) {
+	if len(selectors) == 0 {
+		return nil, nil
+	}
+
+	opts := Eval(`(selectors) => {
+		const origin = this === window ? { x: 0, y: 0 } : this.getBoundingClientRect()
+		const ratio = window.devicePixelRatio
+		const list = []
+		for (const s of selectors) {
+			for (const el of document.querySelectorAll(s)) {
+				const box = el.getBoundingClientRect()
+				list.push([
+					(box.left - origin.x) * ratio,
+					(box.top - origin.y) * ratio,
+					(box.right - origin.x) * ratio,
+					(box.bottom - origin.y) * ratio,
+				].map(Math.round))
+			}
+		}
+		return list
+	}`, selectors)
+	if el != nil {
+		opts = opts.This(el.Object)
+	}
+
+	res, err := p.Evaluate(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	list := []image.Rectangle{}
+	for _, box := range res.Value.Arr() {
+		b := box.Arr()
+		list = append(list, image.Rect(b[0].Int(), b[1].Int(), b[2].Int(), b[3].Int()))
+	}
+	return list, nil
+}
+
+func matchScreenshot(name string, bin []byte, masks []image.Rectangle, opts *VisualOptions) (*VisualResult, error) {
+	res := &VisualResult{Baseline: filepath.Join(opts.Dir, name)}
+
+	baseline, err := ioutil.ReadFile(res.Baseline)
+	if os.IsNotExist(err) || opts.Update {
+		res.Pass = true
+		res.Created = true
+		return res, utils.OutputFile(res.Baseline, bin)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	expected, err := png.Decode(bytes.NewReader(baseline))
+	if err != nil {
+		return nil, err
+	}
+
+	actual, err := png.Decode(bytes.NewReader(bin))
+	if err != nil {
+		return nil, err
+	}
+
+	diff := utils.DiffImage(expected, actual, opts.Threshold, masks...)
+	res.DiffPixels = diff.Count
+	res.DiffRatio = diff.Ratio()
+	res.Pass = expected.Bounds().Size() == actual.Bounds().Size() && res.DiffRatio <= opts.Tolerance
+
+	if res.Pass {
+		return res, nil
+	}
+
+	base := filepath.Join(opts.OutDir, strings.TrimSuffix(name, filepath.Ext(name)))
+	res.Actual = base + ".actual.png"
+	res.Diff = base + ".diff.png"
+
+	err = utils.OutputFile(res.Actual, bin)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err = png.Encode(buf, diff.Image)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, utils.OutputFile(res.Diff, buf.Bytes())
+}
+
+func firstVisualOptions(opts []*VisualOptions) *VisualOptions {
+	if len(opts) == 0 {
+		return nil
+	}
+	return opts[0]
+}
+
+func mustMatchScreenshot(res *VisualResult, err error) error {
+	if err != nil {
+		return err
+	}
+	if !res.Pass {
+		return &ErrScreenshotMismatch{res}
+	}
+	return nil
+}
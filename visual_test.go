@@ -0,0 +1,73 @@
+package rod_test
+
+import (
+	"path/filepath"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/utils"
+)
+
+func (t T) MatchScreenshot() {
+	dir := filepath.Join("tmp", "visual", t.Srand(8))
+	opts := &rod.VisualOptions{Dir: dir, OutDir: dir}
+
+	p := t.newPage(t.srcFile("fixtures/visual.html")).MustWaitLoad()
+
+	res, err := p.MatchScreenshot("page.png", opts)
+	t.E(err)
+	t.True(res.Created)
+	t.True(utils.FileExists(filepath.Join(dir, "page.png")))
+
+	p.MustMatchScreenshot("page.png", opts)
+
+	p.MustElement("#clock").MustEval(`() => this.innerText = "12:34"`)
+
+	res, err = p.MatchScreenshot("page.png", opts)
+	t.E(err)
+	t.False(res.Pass)
+	t.Gt(res.DiffPixels, 0)
+	t.True(utils.FileExists(res.Actual))
+	t.True(utils.FileExists(res.Diff))
+
+	t.Panic(func() { p.MustMatchScreenshot("page.png", opts) })
+	t.Has((&rod.ErrScreenshotMismatch{VisualResult: res}).Error(), "page.png")
+
+	masked := *opts
+	masked.Masks = []string{"#clock"}
+	p.MustMatchScreenshot("page.png", &masked)
+
+	tolerant := *opts
+	tolerant.Tolerance = 0.5
+	p.MustMatchScreenshot("page.png", &tolerant)
+
+	update := *opts
+	update.Update = true
+	res, err = p.MatchScreenshot("page.png", &update)
+	t.E(err)
+	t.True(res.Created)
+	p.MustMatchScreenshot("page.png", opts)
+
+	exact := *opts
+	exact.Exact = true
+	p.MustMatchScreenshot("page.png", &exact)
+
+	box := p.MustElement("#box")
+	box.MustMatchScreenshot("box.png", opts)
+	box.MustElement("#clock").MustEval(`() => this.innerText = "00:00"`)
+	res, err = box.MatchScreenshot("box.png", opts)
+	t.E(err)
+	t.False(res.Pass)
+	box.MustMatchScreenshot("box.png", &masked)
+
+	// the masks of the full page are in the page coordinates
+	full := masked
+	full.FullPage = true
+	p.MustEval(`() => {
+		document.body.style.height = '3000px'
+		document.querySelector('#box').style.marginTop = '2000px'
+		window.scrollTo(0, 1500)
+	}`)
+	p.MustMatchScreenshot("full.png", &full)
+	p.MustElement("#clock").MustEval(`() => this.innerText = "11:11"`)
+	p.MustMatchScreenshot("full.png", &full)
+}
@@ -0,0 +1,409 @@
+package rod
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/rod/lib/defaults"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/go-rod/rod/lib/utils"
+)
+
+// WatchdogOptions for Browser.Watchdog
+type WatchdogOptions struct {
+	// Launcher of the browser, the exit of its process will be detected. If the Browser has no control url
+	// or client when it connects, the Launcher will be launched to get one.
+	Launcher *launcher.Launcher
+
+	// Relaunch the browser via a clone of the Launcher after the browser process exits, then reopen the pages
+	// with their last urls. The existing Page objects keep working, but their TargetID and FrameID are the ones
+	// before the relaunch, and the states of the pages are lost, such as the incognito contexts and the emulations.
+	// The relaunched browser uses a fresh user-data-dir, because the one of the exited browser may be locked or broken.
+	// It requires the Launcher and a *cdp.Client. The BrowserReconnected event is emitted after the pages are restored.
+	Relaunch bool
+}
+
+// Watchdog enables the crash detection of the browser, call it before the Browser.Connect.
+// When the renderer of a page crashes, all the calls on the page fail with ErrPageCrashed, including the in-flight
+// ones, except the Page.Navigate which can recover the page. When the browser process exits, all the calls fail
+// with ErrBrowserExited. Without the watchdog the calls may hang or fail with cdp.ErrConnClosed.
+func (b *Browser) Watchdog(opts *WatchdogOptions) *Browser {
+	if opts == nil {
+		opts = &WatchdogOptions{}
+	}
+
+	b.watchdog = &watchdog{
+		relaunch: opts.Relaunch,
+		launcher: opts.Launcher,
+		crashed:  map[proto.TargetTargetID]chan struct{}{},
+		exited:   make(chan struct{}),
+		urls:     map[proto.TargetTargetID]string{},
+		origins:  map[proto.TargetTargetID]proto.TargetTargetID{},
+	}
+	return b
+}
+
+// the methods that are allowed on a crashed page to recover it
+var watchdogRecoverMethods = map[string]bool{
+	proto.PageStopLoading{}.ProtoReq(): true,
+	proto.PageNavigate{}.ProtoReq():    true,
+	proto.PageReload{}.ProtoReq():      true,
+}
+
+// how long to wait for the crash signals after a call fails with a crash related error
+var watchdogGrace = time.Second
+
+type watchdog struct {
+	relaunch bool
+
+	lock       sync.Mutex
+	launcher   *launcher.Launcher
+	crashed    map[proto.TargetTargetID]chan struct{} // closed when the target crashes
+	exited     chan struct{}                          // closed when the browser exits
+	isExited   bool
+	closing    bool                                          // the browser is closed via Browser.Close
+	relaunched bool                                          // the pages should be restored
+	urls       map[proto.TargetTargetID]string               // the last urls of the pages
+	origins    map[proto.TargetTargetID]proto.TargetTargetID // the restored targets to the original ones
+}
+
+// setup the client of the browser before it connects
+func (w *watchdog) setup(b *Browser) error {
+	l := w.current()
+
+	if b.client == nil && defaults.URL == "" && l != nil {
+		if l.PID() != 0 {
+			return errors.New("the Launcher of the watchdog is launched, use Browser.ControlURL to set its url")
+		}
+		u, err := l.Launch()
+		if err != nil {
+			return err
+		}
+		b.client = cdp.New(u)
+	}
+
+	if l != nil {
+		go w.watchExit(b.ctx, l)
+	}
+
+	if !w.relaunch {
+		return nil
+	}
+
+	if l == nil {
+		return errors.New("the Relaunch of the watchdog requires the Launcher")
+	}
+
+	client, ok := b.client.(*cdp.Client)
+	if !ok {
+		return errors.New("the Relaunch of the watchdog requires a *cdp.Client")
+	}
+
+	client.Reconnect(func() utils.Sleeper {
+		return utils.EachSleepers(utils.CountSleeper(5), utils.BackoffSleeper(100*time.Millisecond, time.Second, nil))
+	}).ReconnectURL(func(ctx context.Context) (string, error) {
+		return w.relaunchBrowser(ctx, b)
+	})
+
+	return nil
+}
+
+func (w *watchdog) current() *launcher.Launcher {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.launcher
+}
+
+func (w *watchdog) watchExit(ctx context.Context, l *launcher.Launcher) {
+	select {
+	case <-ctx.Done():
+	case <-l.Exit():
+		w.exit()
+	}
+}
+
+// watch the crash events of the browser
+func (w *watchdog) watch(b *Browser) {
+	for msg := range b.Event() {
+		crashed := proto.TargetTargetCrashed{}
+		inspectorCrashed := proto.InspectorTargetCrashed{}
+		reloaded := proto.InspectorTargetReloadedAfterCrash{}
+		created := proto.TargetTargetCreated{}
+		changed := proto.TargetTargetInfoChanged{}
+		destroyed := proto.TargetTargetDestroyed{}
+
+		switch {
+		case msg.Load(&crashed):
+			w.crash(w.origin(crashed.TargetID))
+		case msg.Load(&inspectorCrashed):
+			if p := b.pageFromSession(msg.SessionID); p != nil {
+				w.crash(p.TargetID)
+			}
+		case msg.Load(&reloaded):
+			if p := b.pageFromSession(msg.SessionID); p != nil {
+				w.recover(p.TargetID)
+			}
+		case msg.Load(&created):
+			w.setURL(created.TargetInfo)
+		case msg.Load(&changed):
+			w.setURL(changed.TargetInfo)
+		case msg.Load(&destroyed):
+			w.forget(w.origin(destroyed.TargetID))
+		}
+	}
+
+	// the event stream is closed by the browser
+	if b.ctx.Err() == nil {
+		w.exit()
+	}
+}
+
+func (b *Browser) pageFromSession(id proto.TargetSessionID) (page *Page) {
+	b.states.Range(func(_, value interface{}) bool {
+		if p, ok := value.(*Page); ok && p.SessionID == id {
+			page = p
+			return false
+		}
+		return true
+	})
+	return
+}
+
+// call fn with a context that will be canceled when the target crashes or the browser exits,
+// then the typed error will be returned instead.
+func (w *watchdog) call(
+	ctx context.Context, id proto.TargetTargetID, method string, fn func(context.Context) ([]byte, error),
+) ([]byte, error) {
+	crashed, exited := w.signals(id)
+	if watchdogRecoverMethods[method] {
+		crashed = nil
+	}
+
+	if err := w.check(id, crashed, exited); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-done:
+		case <-crashed:
+			cancel()
+		case <-exited:
+			cancel()
+		}
+	}()
+
+	res, err := fn(ctx)
+	if err == nil {
+		return res, nil
+	}
+
+	if e := w.check(id, crashed, exited); e != nil {
+		return nil, e
+	}
+
+	// the error may come before the crash event
+	if errors.Is(err, cdp.ErrConnClosed) || errors.Is(err, cdp.ErrTargetCrashed) {
+		t := time.NewTimer(watchdogGrace)
+		defer t.Stop()
+		select {
+		case <-crashed:
+			return nil, &ErrPageCrashed{TargetID: id}
+		case <-exited:
+			return nil, &ErrBrowserExited{}
+		case <-t.C:
+		}
+	}
+
+	return nil, err
+}
+
+func (w *watchdog) check(id proto.TargetTargetID, crashed, exited chan struct{}) error {
+	select {
+	case <-exited:
+		return &ErrBrowserExited{}
+	default:
+	}
+
+	select {
+	case <-crashed:
+		return &ErrPageCrashed{TargetID: id}
+	default:
+	}
+
+	return nil
+}
+
+// returns the signal channels of the target, the crashed is nil if the id is empty
+func (w *watchdog) signals(id proto.TargetTargetID) (crashed, exited chan struct{}) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if id != "" {
+		crashed = w.crashed[id]
+		if crashed == nil {
+			crashed = make(chan struct{})
+			w.crashed[id] = crashed
+		}
+	}
+
+	return crashed, w.exited
+}
+
+func (w *watchdog) crash(id proto.TargetTargetID) {
+	if id == "" {
+		return
+	}
+
+	crashed, _ := w.signals(id)
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	select {
+	case <-crashed:
+	default:
+		close(crashed)
+	}
+}
+
+// the page is reloaded after the crash
+func (w *watchdog) recover(id proto.TargetTargetID) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	delete(w.crashed, id)
+}
+
+func (w *watchdog) forget(id proto.TargetTargetID) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	delete(w.crashed, id)
+	delete(w.urls, id)
+}
+
+func (w *watchdog) exit() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if !w.isExited {
+		w.isExited = true
+		close(w.exited)
+	}
+}
+
+// the browser is closed by the user, don't relaunch it
+func (w *watchdog) close() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.closing = true
+}
+
+func (w *watchdog) setURL(info *proto.TargetTargetInfo) {
+	if info == nil || info.Type != proto.TargetTargetInfoTypePage {
+		return
+	}
+
+	id := w.origin(info.TargetID)
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.urls[id] = info.URL
+}
+
+func (w *watchdog) origin(id proto.TargetTargetID) proto.TargetTargetID {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if origin, has := w.origins[id]; has {
+		return origin
+	}
+	return id
+}
+
+// relaunchBrowser returns the url of the relaunched browser, it waits until the browser process exits,
+// so the connection that drops while the process is alive won't be recovered until the process exits.
+func (w *watchdog) relaunchBrowser(ctx context.Context, b *Browser) (string, error) {
+	w.lock.Lock()
+	closing := w.closing
+	l := w.launcher
+	w.lock.Unlock()
+
+	if closing {
+		return "", &ErrBrowserExited{}
+	}
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-l.Exit():
+	}
+
+	w.exit()
+
+	b.logger.Println("[rod] relaunch the browser")
+
+	l = l.Clone().Set("user-data-dir", filepath.Join(os.TempDir(), "rod", "user-data", utils.RandString(8)))
+	u, err := l.Launch()
+	if err != nil {
+		return "", err
+	}
+
+	w.lock.Lock()
+	w.launcher = l
+	w.relaunched = true
+	w.lock.Unlock()
+
+	go w.watchExit(b.ctx, l)
+
+	return u, nil
+}
+
+// reset the exit state after the connection is resumed, returns true if the browser is relaunched
+func (w *watchdog) reset() bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	relaunched := w.relaunched
+	w.relaunched = false
+
+	if w.isExited && relaunched {
+		w.isExited = false
+		w.exited = make(chan struct{})
+		w.crashed = map[proto.TargetTargetID]chan struct{}{}
+	}
+
+	return relaunched
+}
+
+// restore the page in the relaunched browser with its last url
+func (w *watchdog) restore(b *Browser, page *Page) (*proto.TargetAttachToTargetResult, error) {
+	w.lock.Lock()
+	u := w.urls[page.TargetID]
+	w.lock.Unlock()
+
+	if u == "" {
+		u = "about:blank"
+	}
+
+	target, err := proto.TargetCreateTarget{URL: u}.Call(b)
+	if err != nil {
+		return nil, err
+	}
+
+	w.lock.Lock()
+	w.origins[target.TargetID] = page.TargetID
+	w.lock.Unlock()
+
+	return proto.TargetAttachToTarget{TargetID: target.TargetID, Flatten: true}.Call(b)
+}
@@ -0,0 +1,273 @@
+package rod
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// WebSocketFrame is a message sent or received by a WebSocket of the page
+type WebSocketFrame struct {
+	// RequestID of the WebSocket
+	RequestID proto.NetworkRequestID
+
+	// URL of the WebSocket
+	URL string
+
+	// Sent is true if the frame is sent by the page, false if it's received by the page
+	Sent bool
+
+	// Opcode of the message, 1 means text, 2 means binary
+	Opcode int
+
+	// Payload of the message. If the Opcode is not 1, it's base64 encoded.
+	Payload string
+
+	// Synthetic is true if the frame is injected via WebSocket.Inject
+	Synthetic bool
+
+	// Time when the event of the frame is received
+	Time time.Time
+}
+
+// WebSocket is a WebSocket of the page tracked by the WebSockets
+type WebSocket struct {
+	sockets *webSocketsState
+
+	// RequestID of the WebSocket
+	RequestID proto.NetworkRequestID
+
+	// URL of the WebSocket
+	URL string
+
+	// Request of the handshake, nil if it hasn't been sent yet
+	Request *proto.NetworkWebSocketRequest
+
+	// Response of the handshake, nil if it hasn't been received yet
+	Response *proto.NetworkWebSocketResponse
+
+	// Frames in the order they are sent or received
+	Frames []*WebSocketFrame
+
+	// ErrorMessage of the last frame error
+	ErrorMessage string
+
+	// Closed is true if the WebSocket is closed
+	Closed bool
+
+	// ClosedAt is the time when the close event is received
+	ClosedAt time.Time
+}
+
+// WebSockets tracks the WebSockets of a page
+type WebSockets struct {
+	ctx   context.Context
+	state *webSocketsState
+}
+
+type webSocketsState struct {
+	changeNotifier
+	page     *Page
+	list     []*WebSocket
+	handlers []func(*WebSocketFrame)
+	stop     func()
+}
+
+// WebSockets starts to track the WebSockets of the page until the WebSockets.Stop is called.
+// The CDP doesn't report the close code and reason, when a WebSocket is closed WebSocket.Closed will be true.
+func (p *Page) WebSockets() *WebSockets {
+	page := p
+	p, cancel := p.WithCancel()
+
+	s := &webSocketsState{
+		changeNotifier: newChangeNotifier(),
+		page:           page,
+		stop:           cancel,
+	}
+
+	frame := func(id proto.NetworkRequestID, sent bool, data *proto.NetworkWebSocketFrame) {
+		s.addFrame(id, &WebSocketFrame{
+			Sent:    sent,
+			Opcode:  int(data.Opcode),
+			Payload: data.PayloadData,
+			Time:    time.Now(),
+		})
+	}
+
+	wait := p.EachEvent(func(e *proto.NetworkWebSocketCreated) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		s.list = append(s.list, &WebSocket{sockets: s, RequestID: e.RequestID, URL: e.URL})
+		s.notify()
+	}, func(e *proto.NetworkWebSocketWillSendHandshakeRequest) {
+		s.update(e.RequestID, func(ws *WebSocket) { ws.Request = e.Request })
+	}, func(e *proto.NetworkWebSocketHandshakeResponseReceived) {
+		s.update(e.RequestID, func(ws *WebSocket) { ws.Response = e.Response })
+	}, func(e *proto.NetworkWebSocketFrameSent) {
+		frame(e.RequestID, true, e.Response)
+	}, func(e *proto.NetworkWebSocketFrameReceived) {
+		frame(e.RequestID, false, e.Response)
+	}, func(e *proto.NetworkWebSocketFrameError) {
+		s.update(e.RequestID, func(ws *WebSocket) { ws.ErrorMessage = e.ErrorMessage })
+	}, func(e *proto.NetworkWebSocketClosed) {
+		s.update(e.RequestID, func(ws *WebSocket) {
+			ws.Closed = true
+			ws.ClosedAt = time.Now()
+		})
+	})
+
+	go wait()
+
+	return &WebSockets{ctx: page.ctx, state: s}
+}
+
+// Context returns a clone with the specified ctx for WebSockets.WaitFrame
+func (s *WebSockets) Context(ctx context.Context) *WebSockets {
+	newObj := *s
+	newObj.ctx = ctx
+	return &newObj
+}
+
+// Timeout returns a clone with the specified timeout for WebSockets.WaitFrame
+func (s *WebSockets) Timeout(d time.Duration) *WebSockets {
+	ctx, cancel := context.WithTimeout(s.ctx, d)
+	return s.Context(context.WithValue(ctx, timeoutContextKey{}, &timeoutContextVal{s.ctx, cancel}))
+}
+
+// Stop tracking, the tracked WebSockets can still be queried
+func (s *WebSockets) Stop() {
+	s.state.stop()
+}
+
+// Handle each frame sent or received after this call, the handler will be called in a new goroutine
+// for each frame. Such as use WebSocket.Inject to mock the replies from the server.
+func (s *WebSockets) Handle(handler func(*WebSocketFrame)) {
+	s.state.lock.Lock()
+	defer s.state.lock.Unlock()
+	s.state.handlers = append(s.state.handlers, handler)
+}
+
+// All the tracked WebSockets in the order they are created
+func (s *WebSockets) All() []*WebSocket {
+	return s.state.snapshot()
+}
+
+// Get the tracked WebSocket by the request id, returns nil if not found
+func (s *WebSockets) Get(id proto.NetworkRequestID) *WebSocket {
+	for _, ws := range s.All() {
+		if ws.RequestID == id {
+			return ws
+		}
+	}
+	return nil
+}
+
+// WaitFrame waits until a frame matches the predicate, the frames that are already tracked will be checked too.
+func (s *WebSockets) WaitFrame(match func(*WebSocketFrame) bool) (*WebSocketFrame, error) {
+	var found *WebSocketFrame
+	err := s.state.wait(s.ctx, func() bool {
+		for _, ws := range s.state.snapshot() {
+			for _, f := range ws.Frames {
+				if match(f) {
+					found = f
+					return true
+				}
+			}
+		}
+		return false
+	})
+	return found, err
+}
+
+// Inject a synthetic text message to the page, the "message" event will be dispatched on the open
+// WebSocket objects in the page that have the same url as this WebSocket. It only works for
+// the WebSockets of the main frame.
+func (ws *WebSocket) Inject(data string) error {
+	p := ws.sockets.page
+
+	prototype, err := p.Evaluate(Eval(`() => WebSocket.prototype`).ByObject())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = p.Release(prototype) }()
+
+	res, err := proto.RuntimeQueryObjects{PrototypeObjectID: prototype.ObjectID}.Call(p)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = p.Release(res.Objects) }()
+
+	_, err = p.Evaluate(Eval(`function (url, data) {
+		for (const ws of this) {
+			if (ws.url === url && ws.readyState === WebSocket.OPEN) {
+				ws.dispatchEvent(new MessageEvent('message', { data, origin: new URL(url).origin }))
+			}
+		}
+	}`, ws.URL, data).This(res.Objects))
+	if err != nil {
+		return err
+	}
+
+	ws.sockets.addFrame(ws.RequestID, &WebSocketFrame{
+		Opcode:    1,
+		Payload:   data,
+		Synthetic: true,
+		Time:      time.Now(),
+	})
+	return nil
+}
+
+func (s *webSocketsState) addFrame(id proto.NetworkRequestID, f *WebSocketFrame) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	ws := s.find(id)
+	if ws == nil {
+		return
+	}
+
+	f.RequestID = ws.RequestID
+	f.URL = ws.URL
+	ws.Frames = append(ws.Frames, f)
+	s.notify()
+
+	for _, h := range s.handlers {
+		go h(f)
+	}
+}
+
+func (s *webSocketsState) update(id proto.NetworkRequestID, fn func(*WebSocket)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if ws := s.find(id); ws != nil {
+		fn(ws)
+		s.notify()
+	}
+}
+
+// find the WebSocket, must be called with the lock held
+func (s *webSocketsState) find(id proto.NetworkRequestID) *WebSocket {
+	for _, ws := range s.list {
+		if ws.RequestID == id {
+			return ws
+		}
+	}
+	return nil
+}
+
+// copy the WebSockets and their frames so that the updates from the events won't race with the readers
+func (s *webSocketsState) snapshot() []*WebSocket {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	list := make([]*WebSocket, len(s.list))
+	for i, ws := range s.list {
+		cp := *ws
+		cp.Frames = append([]*WebSocketFrame{}, ws.Frames...)
+		list[i] = &cp
+	}
+	return list
+}
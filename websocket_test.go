@@ -0,0 +1,77 @@
+package rod_test
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/utils"
+)
+
+func (t T) WebSockets() {
+	s := t.Serve()
+	s.Route("/", ".html", `<html><body><script>
+		window.messages = []
+		function connect() {
+			window.ws = new WebSocket(location.href.replace('http', 'ws') + 'echo')
+			ws.onmessage = (e) => messages.push(e.data)
+			ws.onopen = () => ws.send('hello')
+		}
+	</script></body></html>`)
+	s.Mux.HandleFunc("/echo", serveEchoWebSocket)
+
+	page := t.newPage(s.URL()).MustWaitLoad()
+
+	sockets := page.WebSockets()
+	defer sockets.Stop()
+
+	sockets.Handle(func(f *rod.WebSocketFrame) {
+		if !f.Sent && f.Payload == "hello" {
+			t.E(sockets.Get(f.RequestID).Inject("mocked"))
+		}
+	})
+
+	page.MustEval(`() => connect()`)
+
+	sent := sockets.MustWaitFrame(func(f *rod.WebSocketFrame) bool { return f.Sent })
+	t.Eq("hello", sent.Payload)
+	t.Eq(1, sent.Opcode)
+	t.Eq(s.URL("/echo")[len("http"):], sent.URL[len("ws"):])
+
+	synthetic := sockets.MustWaitFrame(func(f *rod.WebSocketFrame) bool { return f.Synthetic })
+	t.Eq("mocked", synthetic.Payload)
+
+	page.MustWait(`messages.join(',') === 'hello,mocked'`)
+
+	page.MustEval(`() => ws.close()`)
+	t.E(utils.Retry(t.Context(), utils.BackoffSleeper(10*time.Millisecond, 100*time.Millisecond, nil), func() (bool, error) {
+		return sockets.All()[0].Closed, nil
+	}))
+
+	ws := sockets.All()[0]
+	t.Eq(101, ws.Response.Status)
+	t.Gte(len(ws.Frames), 3)
+
+	_, err := sockets.Timeout(time.Millisecond).WaitFrame(func(f *rod.WebSocketFrame) bool { return false })
+	t.Eq(err, context.DeadlineExceeded)
+}
+
+// serveEchoWebSocket echoes the first text message
+func serveEchoWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := utils.UpgradeWebSocket(w, r)
+	if err != nil {
+		return
+	}
+	defer func() { _ = conn.Close() }()
+
+	msg, err := conn.Read()
+	if err != nil {
+		return
+	}
+
+	_ = conn.Write(msg)
+
+	// wait for the client to close
+	_, _ = conn.Read()
+}